@@ -3,18 +3,17 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/google/uuid"
-
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/alerting"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/config"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/mq"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
 )
 
@@ -31,11 +30,19 @@ func main() {
 	defer dbPool.Close()
 
 	repo := storage.NewRepository(dbPool)
+	policy := alerting.PolicyFromConfig(cfg)
+	scorer, err := risk.LookupScorer(cfg.RiskScoringModel)
+	if err != nil {
+		log.Fatalf("alert-service scoring model error: %v", err)
+	}
+	if err := policy.Check(scorer); err != nil {
+		log.Fatalf("alert-service policy error: %v", err)
+	}
 
 	reader := mq.NewReader(cfg.KafkaBrokers, cfg.KafkaTopicRisk, cfg.ConsumerGroupPrefix+"-alert-service")
 	defer reader.Close()
 
-	log.Printf("alert-service consuming %s threshold=%.2f", cfg.KafkaTopicRisk, cfg.AlertThreshold)
+	log.Printf("alert-service consuming %s threshold=%.2f auto_resolve_below=%.2f escalate_velocity=%.2f require_lower_bound=%t", cfg.KafkaTopicRisk, cfg.AlertThreshold, cfg.AlertAutoResolveBelow, cfg.AlertEscalateVelocity, cfg.AlertRequireLowerBound)
 
 	for {
 		msg, err := reader.ReadMessage(ctx)
@@ -55,11 +62,20 @@ func main() {
 			continue
 		}
 
-		if riskEvent.RiskScore < cfg.AlertThreshold {
+		switch policy.Decide(riskEvent) {
+		case alerting.DecisionResolve:
+			resolved, err := repo.ResolveOpenAlerts(ctx, riskEvent.Country, riskEvent.Region, riskEvent.Commodity)
+			if err != nil {
+				log.Printf("alert-service auto-resolve error: %v", err)
+			} else if resolved > 0 {
+				log.Printf("alerts auto-resolved count=%d country=%s region=%s commodity=%s score=%.2f", resolved, riskEvent.Country, riskEvent.Region, riskEvent.Commodity, riskEvent.RiskScore)
+			}
+			continue
+		case alerting.DecisionNone:
 			continue
 		}
 
-		exists, err := repo.HasOpenAlertInCooldown(ctx, riskEvent.Country, riskEvent.Region, riskEvent.Commodity, cfg.AlertCooldown)
+		exists, err := repo.HasOpenAlertInCooldown(ctx, riskEvent.Country, riskEvent.Region, riskEvent.Commodity, policy.Cooldown)
 		if err != nil {
 			log.Printf("alert-service cooldown check error: %v", err)
 			continue
@@ -68,19 +84,7 @@ func main() {
 			continue
 		}
 
-		alert := contracts.AlertRecord{
-			ID:          uuid.NewString(),
-			RiskEventID: riskEvent.ID,
-			Country:     riskEvent.Country,
-			Region:      riskEvent.Region,
-			Commodity:   riskEvent.Commodity,
-			Title:       fmt.Sprintf("High disruption risk for %s", riskEvent.Commodity),
-			Description: fmt.Sprintf("%s/%s scored %.2f. %s", riskEvent.Country, riskEvent.Region, riskEvent.RiskScore, riskEvent.RecommendedAction),
-			RiskScore:   riskEvent.RiskScore,
-			Severity:    severityFromScore(riskEvent.RiskScore),
-			Status:      "open",
-		}
-
+		alert := policy.NewAlert(riskEvent)
 		if err := repo.InsertAlert(ctx, alert); err != nil {
 			log.Printf("alert-service insert alert error: %v", err)
 			continue
@@ -89,16 +93,3 @@ func main() {
 		log.Printf("alert created id=%s country=%s commodity=%s score=%.2f", alert.ID, alert.Country, alert.Commodity, alert.RiskScore)
 	}
 }
-
-func severityFromScore(score float64) string {
-	switch {
-	case score >= 90:
-		return "critical"
-	case score >= 75:
-		return "high"
-	case score >= 60:
-		return "medium"
-	default:
-		return "low"
-	}
-}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/httpx"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/idempotency"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/mq"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
+)
+
+// maxBatchBytes bounds the body of a batch request.
+const maxBatchBytes = 32 << 20
+
+const (
+	batchAccepted = "accepted"
+	batchRejected = "rejected"
+)
+
+// batchItem reports what happened to one item of a batch, by its position
+// in the request. Accepted items carry the enriched signal, and replays the
+// one accepted first, like the response of POST /v1/signals.
+type batchItem struct {
+	Index    int                    `json:"index"`
+	ID       string                 `json:"id,omitempty"`
+	Status   string                 `json:"status"`
+	Reason   string                 `json:"reason,omitempty"`
+	Problems validate.Problems      `json:"problems,omitempty"`
+	Warnings validate.Problems      `json:"warnings,omitempty"`
+	Replay   bool                   `json:"replay,omitempty"`
+	Signal   *contracts.SignalEvent `json:"signal,omitempty"`
+}
+
+// messageWriter is the part of *kafka.Writer the batch handler uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type batchHandler struct {
+	writer    messageWriter
+	accepted  *idempotency.Cache[contracts.SignalEvent]
+	validator signalValidator
+	maxItems  int
+}
+
+// ServeHTTP accepts a JSON array of signals or an NDJSON stream with one
+// signal per line. Every valid item is enriched and all of them are
+// published with a single WriteMessages call; invalid items are reported
+// and skipped without failing the rest of the batch.
+func (h batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": "request body is required"})
+		return
+	}
+	defer r.Body.Close()
+
+	mode, err := h.validator.modeFor(r)
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+
+	raw, err := readBatch(http.MaxBytesReader(w, r.Body, maxBatchBytes), h.maxItems)
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+
+	items := make([]batchItem, len(raw))
+	pending := make([]int, 0, len(raw))
+	signals := make([]contracts.SignalEvent, len(raw))
+	messages := make([]kafka.Message, 0, len(raw))
+	// first maps a client ID to the first item in this batch that carries
+	// it. Later copies take that item's outcome once the write is done.
+	first := make(map[string]int)
+	copies := make(map[int]int)
+	for i, body := range raw {
+		items[i] = batchItem{Index: i, Status: batchRejected}
+
+		var payload contracts.SignalEvent
+		if err := decodeItem(body, &payload); err != nil {
+			items[i].Reason = err.Error()
+			continue
+		}
+		clientID := strings.TrimSpace(payload.ID)
+		items[i].ID = clientID
+		warnings, err := h.validator.prepare(&payload, mode)
+		items[i].Warnings = warnings
+		if err != nil {
+			items[i].Reason = err.Error()
+			errors.As(err, &items[i].Problems)
+			continue
+		}
+		items[i].ID = payload.ID
+		if clientID != "" {
+			if original, ok := first[payload.ID]; ok {
+				copies[i] = original
+				continue
+			}
+			first[payload.ID] = i
+			if original, fresh := h.accepted.Remember(payload.ID, payload); !fresh {
+				items[i].Status = batchAccepted
+				items[i].Replay = true
+				items[i].Signal = &original
+				continue
+			}
+		}
+
+		msg, err := mq.EncodeJSON(payload.Key(), payload)
+		if err != nil {
+			h.accepted.Forget(payload.ID)
+			items[i].Reason = err.Error()
+			continue
+		}
+		signals[i] = payload
+		pending = append(pending, i)
+		messages = append(messages, msg)
+	}
+
+	status := http.StatusAccepted
+	if len(messages) > 0 {
+		err := h.writer.WriteMessages(r.Context(), messages...)
+		var writeErrs kafka.WriteErrors
+		switch {
+		case err == nil:
+		case errors.As(err, &writeErrs) && len(writeErrs) == len(messages):
+			for n, i := range pending {
+				if writeErrs[n] != nil {
+					h.accepted.Forget(signals[i].ID)
+					items[i].Reason = writeErrs[n].Error()
+					pending[n] = -1
+				}
+			}
+		default:
+			for n, i := range pending {
+				h.accepted.Forget(signals[i].ID)
+				items[i].Reason = err.Error()
+				pending[n] = -1
+			}
+			status = http.StatusInternalServerError
+		}
+		for _, i := range pending {
+			if i >= 0 {
+				items[i].Status = batchAccepted
+				items[i].Signal = &signals[i]
+			}
+		}
+	}
+
+	for i, original := range copies {
+		if items[original].Status == batchAccepted {
+			items[i].Status = batchAccepted
+			items[i].Replay = true
+			items[i].Signal = items[original].Signal
+			continue
+		}
+		items[i].Reason = fmt.Sprintf("duplicate of item %d, which was not accepted", original)
+	}
+
+	accepted, rejected := 0, 0
+	for _, item := range items {
+		if item.Status == batchAccepted {
+			accepted++
+		} else {
+			rejected++
+		}
+	}
+	if accepted == 0 && status == http.StatusAccepted {
+		status = http.StatusBadRequest
+	}
+
+	httpx.WriteJSON(w, status, map[string]any{
+		"received": len(items),
+		"accepted": accepted,
+		"rejected": rejected,
+		"items":    items,
+	})
+}
+
+// readBatch splits the body into one raw JSON value per item. A body whose
+// first character is '[' is read as a JSON array, anything else as NDJSON
+// with blank lines ignored. Both are read item by item and reading stops as
+// soon as the batch exceeds maxItems.
+func readBatch(body io.Reader, maxItems int) ([]json.RawMessage, error) {
+	reader := bufio.NewReader(body)
+	first, err := peekNonSpace(reader)
+	if err == io.EOF {
+		return nil, errors.New("batch is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if first == '[' {
+		decoder := json.NewDecoder(reader)
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+		for decoder.More() {
+			var item json.RawMessage
+			if err := decoder.Decode(&item); err != nil {
+				return nil, fmt.Errorf("invalid JSON array: %w", err)
+			}
+			items = append(items, item)
+			if maxItems > 0 && len(items) > maxItems {
+				return nil, fmt.Errorf("batch has more than %d items", maxItems)
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+	} else {
+		for {
+			line, err := reader.ReadBytes('\n')
+			if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+				items = append(items, json.RawMessage(trimmed))
+				if maxItems > 0 && len(items) > maxItems {
+					break
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("batch is empty")
+	}
+	if maxItems > 0 && len(items) > maxItems {
+		return nil, fmt.Errorf("batch has more than %d items", maxItems)
+	}
+	return items, nil
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, reader.UnreadByte()
+	}
+}
+
+func decodeItem(body json.RawMessage, dst *contracts.SignalEvent) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return fmt.Errorf("invalid signal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/idempotency"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
+)
+
+// fakeWriter records published messages. fail makes the write of every
+// message whose key contains it fail.
+type fakeWriter struct {
+	fail     string
+	err      error
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	var errs kafka.WriteErrors
+	failed := false
+	for _, msg := range msgs {
+		if w.fail != "" && strings.Contains(string(msg.Key), w.fail) {
+			errs = append(errs, errors.New("broker unavailable"))
+			failed = true
+			continue
+		}
+		errs = append(errs, nil)
+		w.messages = append(w.messages, msg)
+	}
+	if failed {
+		return errs
+	}
+	return nil
+}
+
+func newTestBatchHandler(t *testing.T, writer messageWriter, maxItems int) batchHandler {
+	t.Helper()
+	catalog, err := validate.LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return batchHandler{
+		writer:    writer,
+		accepted:  idempotency.New[contracts.SignalEvent](time.Hour, 100),
+		validator: signalValidator{validator: validate.New(validate.Options{Catalog: catalog}), mode: validate.ModeLenient},
+		maxItems:  maxItems,
+	}
+}
+
+type batchResponse struct {
+	Received int         `json:"received"`
+	Accepted int         `json:"accepted"`
+	Rejected int         `json:"rejected"`
+	Items    []batchItem `json:"items"`
+	Error    string      `json:"error"`
+}
+
+func postBatch(t *testing.T, h batchHandler, body string) (int, batchResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/signals:batch", strings.NewReader(body)))
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func statuses(resp batchResponse) string {
+	out := make([]string, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		s := item.Status
+		if item.Replay {
+			s += "+replay"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestBatchHandler(t *testing.T) {
+	wheat := `{"id":"a","source":"weather","country":"BR","commodity":"wheat","severity":6}`
+	rice := `{"id":"b","source":"weather","country":"IN","commodity":"rice","severity":6}`
+	tests := []struct {
+		name          string
+		writer        *fakeWriter
+		body          string
+		wantStatus    int
+		wantItems     string
+		wantPublished int
+	}{
+		{"ndjson", &fakeWriter{}, wheat + "\n\n" + rice + "\n", http.StatusAccepted, "accepted,accepted", 2},
+		{"json array", &fakeWriter{}, "[" + wheat + "," + rice + "]", http.StatusAccepted, "accepted,accepted", 2},
+		{"invalid item", &fakeWriter{}, wheat + "\n" + `{"country":"XX","commodity":"wheat"}`, http.StatusAccepted, "accepted,rejected", 1},
+		{"none valid", &fakeWriter{}, `{"country":"XX","commodity":"wheat"}`, http.StatusBadRequest, "rejected", 0},
+		{"duplicate in batch", &fakeWriter{}, wheat + "\n" + wheat, http.StatusAccepted, "accepted,accepted+replay", 1},
+		// The copy must not be reported as accepted when the first
+		// copy's write fails.
+		{"duplicate of failed write", &fakeWriter{fail: "BR"}, wheat + "\n" + rice + "\n" + wheat, http.StatusAccepted, "rejected,accepted,rejected", 1},
+		{"write fails", &fakeWriter{err: errors.New("kafka down")}, wheat + "\n" + wheat, http.StatusInternalServerError, "rejected,rejected", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := postBatch(t, newTestBatchHandler(t, tt.writer, 10), tt.body)
+			if code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %+v", code, tt.wantStatus, resp)
+			}
+			if got := statuses(resp); got != tt.wantItems {
+				t.Fatalf("items = %s, want %s: %+v", got, tt.wantItems, resp.Items)
+			}
+			if len(tt.writer.messages) != tt.wantPublished {
+				t.Fatalf("published %d messages, want %d", len(tt.writer.messages), tt.wantPublished)
+			}
+		})
+	}
+}
+
+func TestBatchHandlerReplaysAcrossRequests(t *testing.T) {
+	writer := &fakeWriter{}
+	h := newTestBatchHandler(t, writer, 10)
+	_, first := postBatch(t, h, `{"id":"a","source":"weather","country":"br","commodity":"Wheat","severity":6}`)
+	// The retry differs, but reports the signal that was accepted first.
+	_, resp := postBatch(t, h, `{"id":"a","source":"weather","country":"BR","commodity":"wheat","severity":9}`)
+	if got := statuses(resp); got != "accepted+replay" || len(writer.messages) != 1 {
+		t.Fatalf("retry = %s with %d messages published", got, len(writer.messages))
+	}
+	cached, replayed := first.Items[0].Signal, resp.Items[0].Signal
+	if cached == nil || replayed == nil || replayed.ID != "a" || replayed.Key() != "BR|global|wheat" || replayed.Severity != 6 || !replayed.Timestamp.Equal(cached.Timestamp) {
+		t.Fatalf("replayed signal = %+v, want the cached %+v", replayed, cached)
+	}
+}
+
+func TestBatchHandlerReplayInBatch(t *testing.T) {
+	h := newTestBatchHandler(t, &fakeWriter{}, 10)
+	wheat := `{"id":"a","source":"weather","country":"BR","commodity":"wheat","severity":6}`
+	_, resp := postBatch(t, h, wheat+"\n"+wheat)
+	if got := statuses(resp); got != "accepted,accepted+replay" {
+		t.Fatalf("items = %s", got)
+	}
+	if s := resp.Items[1].Signal; s == nil || s.ID != "a" || s.Key() != "BR|global|wheat" {
+		t.Fatalf("replayed signal = %+v, want the first copy", s)
+	}
+}
+
+// failingReader fails once it is read past the given number of bytes, so a
+// test can tell whether readBatch stopped early.
+type failingReader struct {
+	r     *strings.Reader
+	limit int
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if int(f.r.Size())-f.r.Len() >= f.limit {
+		return 0, errors.New("read past the item limit")
+	}
+	if len(p) > 64 {
+		p = p[:64]
+	}
+	return f.r.Read(p)
+}
+
+func TestReadBatch(t *testing.T) {
+	item := `{"country":"BR","commodity":"wheat"}`
+	many := strings.Repeat(item+",", 999) + item
+	tests := []struct {
+		name    string
+		body    string
+		max     int
+		want    int
+		wantErr string
+	}{
+		{"array", "[" + item + "," + item + "]", 5, 2, ""},
+		{"ndjson with blank lines", item + "\n\n" + item + "\n", 5, 2, ""},
+		{"leading whitespace", "\n  [" + item + "]", 5, 1, ""},
+		{"empty", "  ", 5, 0, "batch is empty"},
+		{"empty array", "[]", 5, 0, "batch is empty"},
+		{"array over limit", "[" + many + "]", 3, 0, "more than 3 items"},
+		{"ndjson over limit", strings.Repeat(item+"\n", 4), 3, 0, "more than 3 items"},
+		{"malformed array", "[" + item + ",", 5, 0, "invalid JSON array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Stopping at the limit means the reader never gets near the
+			// end of an oversized body.
+			reader := &failingReader{r: strings.NewReader(tt.body), limit: 4096}
+			items, err := readBatch(reader, tt.max)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != tt.want {
+				t.Fatalf("items = %d, want %d", len(items), tt.want)
+			}
+		})
+	}
+}
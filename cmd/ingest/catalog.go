@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
+)
+
+// loadStoredCatalog reads the catalog managed through query-api. It returns
+// nil when the tables are still empty, e.g. before query-api has seeded
+// them, so the caller keeps the catalog it has.
+func loadStoredCatalog(ctx context.Context, repo *storage.Repository) (*validate.Catalog, error) {
+	commodities, err := repo.ListCatalogCommodities(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	regions, err := repo.ListCatalogRegions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(commodities) == 0 && len(regions) == 0 {
+		return nil, nil
+	}
+
+	latest := time.Time{}
+	for _, c := range commodities {
+		if c.UpdatedAt.After(latest) {
+			latest = c.UpdatedAt
+		}
+	}
+	for _, r := range regions {
+		if r.UpdatedAt.After(latest) {
+			latest = r.UpdatedAt
+		}
+	}
+	version := fmt.Sprintf("postgres@%s/%d+%d", latest.UTC().Format(time.RFC3339), len(commodities), len(regions))
+	return validate.NewCatalog(version, commodities, regions)
+}
+
+// reloadCatalog swaps the stored catalog into the validator when it
+// changed.
+func reloadCatalog(ctx context.Context, repo *storage.Repository, validator *validate.Validator) {
+	catalog, err := loadStoredCatalog(ctx, repo)
+	if err != nil {
+		log.Printf("ingest catalog refresh error: %v", err)
+		return
+	}
+	if catalog == nil || catalog.Version == validator.Catalog().Version {
+		return
+	}
+	validator.SetCatalog(catalog)
+	log.Printf("ingest catalog loaded %s", catalog.Version)
+}
+
+func refreshCatalog(ctx context.Context, repo *storage.Repository, validator *validate.Validator, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloadCatalog(ctx, repo, validator)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/connectors"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/idempotency"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/mq"
+)
+
+// connectorSink publishes connector signals the way POST /v1/signals does:
+// validated in the configured mode, deduplicated by ID and written with
+// mq.PublishJSON.
+func connectorSink(writer *kafka.Writer, accepted *idempotency.Cache[contracts.SignalEvent], validator signalValidator) connectors.Sink {
+	return func(ctx context.Context, s contracts.SignalEvent) error {
+		warnings, err := validator.prepare(&s, validator.mode)
+		if err != nil {
+			return fmt.Errorf("%w: %v", connectors.ErrRejected, err)
+		}
+		if len(warnings) > 0 {
+			log.Printf("connector signal %s: %v", s.ID, warnings)
+		}
+
+		if _, fresh := accepted.Remember(s.ID, s); !fresh {
+			return nil
+		}
+		if err := mq.PublishJSON(ctx, writer, s.Key(), s); err != nil {
+			accepted.Forget(s.ID)
+			return err
+		}
+		return nil
+	}
+}
@@ -16,10 +16,16 @@ import (
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/ais"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/config"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/connectors"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/httpx"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/idempotency"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/mq"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
 )
 
 func main() {
@@ -31,6 +37,75 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	accepted := idempotency.New[contracts.SignalEvent](cfg.IngestDedupTTL, cfg.IngestDedupMaxEntries)
+
+	catalog, err := validate.LoadCatalog(cfg.IngestCatalogFile)
+	if err != nil {
+		log.Fatalf("ingest catalog error: %v", err)
+	}
+	mode, err := validate.ParseMode(cfg.IngestValidationMode)
+	if err != nil {
+		log.Fatalf("validation mode: %v", err)
+	}
+	policy, err := risk.LoadPolicyStore(cfg.RiskWeightPolicyFile)
+	if err != nil {
+		log.Fatalf("ingest weight policy error: %v", err)
+	}
+	validator := signalValidator{
+		validator: validate.New(validate.Options{Catalog: catalog, Sources: policy.Current().KnownSources(), MaxFuture: cfg.IngestMaxFuture}),
+		mode:      mode,
+	}
+	go watchSources(ctx, policy, validator.validator, cfg.RiskPolicyReload)
+	specs, err := connectors.Load(cfg.IngestConnectorsFile)
+	if err != nil {
+		log.Fatalf("ingest connectors error: %v", err)
+	}
+
+	var repo *storage.Repository
+	if cfg.IngestCatalogFile == "" || len(specs) > 0 {
+		dbPool, err := storage.Open(ctx, cfg.DatabaseURL)
+		if err != nil {
+			log.Fatalf("ingest database error: %v", err)
+		}
+		defer dbPool.Close()
+		repo = storage.NewRepository(dbPool)
+	}
+	if cfg.IngestCatalogFile == "" {
+		reloadCatalog(ctx, repo, validator.validator)
+		if cfg.IngestCatalogRefresh > 0 {
+			go refreshCatalog(ctx, repo, validator.validator, cfg.IngestCatalogRefresh)
+		}
+	}
+	log.Printf("ingest validation mode=%s catalog=%s policy=%s max_future=%s", mode, validator.validator.Catalog().Version, policy.Current().Version, cfg.IngestMaxFuture)
+
+	runner := connectors.NewRunner(repo, connectorSink(writer, accepted, validator))
+	for _, spec := range specs {
+		c, err := connectors.New(spec, &http.Client{Timeout: 30 * time.Second})
+		if err != nil {
+			log.Fatalf("ingest connectors error: %v", err)
+		}
+		runner.Add(spec, c)
+		log.Printf("ingest connector %s type=%s interval=%s", spec.Name, spec.Type, spec.Interval())
+	}
+	if len(specs) > 0 {
+		go runner.Run(ctx)
+	}
+
+	var feed *aisFeed
+	if cfg.IngestAISSource != "" {
+		lanes, err := ais.LoadLanes(cfg.IngestAISLanesFile)
+		if err != nil {
+			log.Fatalf("ingest ais lanes error: %v", err)
+		}
+		feed = &aisFeed{
+			source:  cfg.IngestAISSource,
+			tracker: ais.NewTracker(lanes, ais.Options{Window: cfg.IngestAISWindow, StaleAfter: cfg.IngestAISStaleAfter, AllowedLateness: cfg.IngestAISAllowedLateness}),
+			sink:    connectorSink(writer, accepted, validator),
+		}
+		go feed.run(ctx)
+		log.Printf("ingest ais source=%s lanes=%d window=%s", cfg.IngestAISSource, len(lanes), cfg.IngestAISWindow)
+	}
+
 	if cfg.SimulatorTick > 0 {
 		go runSimulator(ctx, writer, cfg.SimulatorTick)
 	}
@@ -47,13 +122,29 @@ func main() {
 			return
 		}
 
-		if strings.TrimSpace(payload.Country) == "" || strings.TrimSpace(payload.Commodity) == "" {
-			httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": "country and commodity are required"})
+		mode, err := validator.modeFor(r)
+		if err != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+
+		clientID := strings.TrimSpace(payload.ID)
+		warnings, err := validator.prepare(&payload, mode)
+		if err != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, rejection(err))
 			return
 		}
+		setWarningHeaders(w, warnings)
+		if clientID != "" {
+			if original, fresh := accepted.Remember(payload.ID, payload); !fresh {
+				w.Header().Set("Idempotent-Replay", "true")
+				httpx.WriteJSON(w, http.StatusAccepted, original)
+				return
+			}
+		}
 
-		enrichSignal(&payload)
 		if err := mq.PublishJSON(r.Context(), writer, payload.Key(), payload); err != nil {
+			accepted.Forget(payload.ID)
 			httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
 			return
 		}
@@ -61,6 +152,20 @@ func main() {
 		httpx.WriteJSON(w, http.StatusAccepted, payload)
 	})
 
+	router.Method(http.MethodPost, "/v1/signals:batch", batchHandler{writer: writer, accepted: accepted, validator: validator, maxItems: cfg.IngestBatchMaxItems})
+
+	router.Get("/v1/connectors", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": runner.Status()})
+	})
+
+	router.Get("/v1/ais/lanes", func(w http.ResponseWriter, r *http.Request) {
+		if feed == nil {
+			httpx.WriteJSON(w, http.StatusNotFound, map[string]any{"error": "ais feed is not configured"})
+			return
+		}
+		feed.ServeHTTP(w, r)
+	})
+
 	router.Post("/v1/simulate", func(w http.ResponseWriter, r *http.Request) {
 		type req struct {
 			Count int `json:"count"`
@@ -124,39 +229,6 @@ func runSimulator(ctx context.Context, writer *kafka.Writer, tick time.Duration)
 	}
 }
 
-func enrichSignal(s *contracts.SignalEvent) {
-	if s.ID == "" {
-		s.ID = uuid.NewString()
-	}
-	if s.Timestamp.IsZero() {
-		s.Timestamp = time.Now().UTC()
-	}
-	s.Country = strings.ToUpper(strings.TrimSpace(s.Country))
-	s.Region = strings.TrimSpace(s.Region)
-	if s.Region == "" {
-		s.Region = "global"
-	}
-	s.Commodity = strings.ToLower(strings.TrimSpace(s.Commodity))
-	if s.Severity < 1 {
-		s.Severity = 1
-	}
-	if s.Severity > 10 {
-		s.Severity = 10
-	}
-	if s.Confidence <= 0 {
-		s.Confidence = 0.6
-	}
-	if s.Confidence > 1 {
-		s.Confidence = 1
-	}
-	if s.Source == "" {
-		s.Source = contracts.SourceNews
-	}
-	if s.MetricName == "" {
-		s.MetricName = "composite_signal"
-	}
-}
-
 func randomSignal() contracts.SignalEvent {
 	countries := []string{"US", "DE", "IN", "BR", "ZA", "ID", "JP", "NG"}
 	regions := []string{"north", "south", "west", "east", "metro", "coastal"}
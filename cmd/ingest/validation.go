@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
+)
+
+// signalValidator applies the configured validation mode unless a request
+// asks for another one with ?validation=strict|lenient.
+type signalValidator struct {
+	validator *validate.Validator
+	mode      validate.Mode
+}
+
+func (v signalValidator) modeFor(r *http.Request) (validate.Mode, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("validation"))
+	if raw == "" {
+		return v.mode, nil
+	}
+	return validate.ParseMode(raw)
+}
+
+// prepare fills in defaults and validates the signal.
+func (v signalValidator) prepare(s *contracts.SignalEvent, mode validate.Mode) (validate.Problems, error) {
+	s.Enrich(time.Now().UTC())
+	return v.validator.Check(s, mode)
+}
+
+// rejection is the response body of a signal that failed validation.
+func rejection(err error) map[string]any {
+	body := map[string]any{"error": err.Error()}
+	var problems validate.Problems
+	if errors.As(err, &problems) {
+		body["problems"] = problems
+	}
+	return body
+}
+
+func setWarningHeaders(w http.ResponseWriter, warnings validate.Problems) {
+	for _, warning := range warnings {
+		w.Header().Add("Validation-Warning", warning.String())
+	}
+}
+
+// watchSources keeps the validator's known sources in step with the risk
+// weight policy, which the risk engine reloads on the same interval.
+func watchSources(ctx context.Context, policy *risk.PolicyStore, validator *validate.Validator, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := policy.Reload()
+			if err != nil {
+				log.Printf("ingest weight policy reload error: %v", err)
+				continue
+			}
+			if changed {
+				validator.SetSources(policy.Current().KnownSources())
+				log.Printf("ingest weight policy reloaded version=%s", policy.Current().Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/httpx"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
+)
+
+// catalogStore is the part of *storage.Repository the catalog API uses.
+type catalogStore interface {
+	ListCatalogCommodities(ctx context.Context, category string) ([]contracts.CatalogCommodity, error)
+	GetCatalogCommodity(ctx context.Context, name string) (contracts.CatalogCommodity, error)
+	PutCatalogCommodity(ctx context.Context, item contracts.CatalogCommodity, check storage.CatalogCheck) (contracts.CatalogCommodity, error)
+	DeleteCatalogCommodity(ctx context.Context, name string) error
+	ListCatalogRegions(ctx context.Context, country string) ([]contracts.CatalogRegion, error)
+	PutCatalogRegion(ctx context.Context, item contracts.CatalogRegion, check storage.CatalogCheck) (contracts.CatalogRegion, error)
+	DeleteCatalogRegion(ctx context.Context, country, name string, check storage.CatalogCheck) error
+}
+
+// catalogAPI edits the commodity and region reference catalog. Every change
+// is checked against the whole catalog with validate.NewCatalog, in the
+// transaction that writes it, so names and aliases stay unambiguous and the
+// region hierarchy stays a tree.
+type catalogAPI struct {
+	repo catalogStore
+}
+
+// catalogConflict is a change the rest of the catalog does not allow.
+type catalogConflict struct {
+	err error
+}
+
+func (c catalogConflict) Error() string { return c.err.Error() }
+
+func (c catalogAPI) routes(router chi.Router) {
+	router.Get("/v1/catalog/commodities", c.listCommodities)
+	router.Get("/v1/catalog/commodities/{name}", c.getCommodity)
+	router.Put("/v1/catalog/commodities/{name}", c.putCommodity)
+	router.Delete("/v1/catalog/commodities/{name}", c.deleteCommodity)
+	router.Get("/v1/catalog/regions", c.listRegions)
+	router.Get("/v1/catalog/regions/{name}", c.getRegion)
+	router.Put("/v1/catalog/regions/{name}", c.putRegion)
+	router.Delete("/v1/catalog/regions/{name}", c.deleteRegion)
+}
+
+func (c catalogAPI) load(ctx context.Context) ([]contracts.CatalogCommodity, []contracts.CatalogRegion, error) {
+	commodities, err := c.repo.ListCatalogCommodities(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	regions, err := c.repo.ListCatalogRegions(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return commodities, regions, nil
+}
+
+func (c catalogAPI) listCommodities(w http.ResponseWriter, r *http.Request) {
+	items, err := c.repo.ListCatalogCommodities(r.Context(), strings.TrimSpace(r.URL.Query().Get("category")))
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (c catalogAPI) getCommodity(w http.ResponseWriter, r *http.Request) {
+	item, err := c.repo.GetCatalogCommodity(r.Context(), validate.CanonicalName(chi.URLParam(r, "name")))
+	if err != nil {
+		writeCatalogError(w, "commodity", err)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, item)
+}
+
+func (c catalogAPI) putCommodity(w http.ResponseWriter, r *http.Request) {
+	var item contracts.CatalogCommodity
+	if err := httpx.DecodeJSON(r, &item); err != nil {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	name := validate.CanonicalName(chi.URLParam(r, "name"))
+	if item.Name != "" && validate.CanonicalName(item.Name) != name {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": "name in body does not match the path"})
+		return
+	}
+	item.Name = name
+	item.DisplayName = strings.TrimSpace(item.DisplayName)
+	item.Category = validate.CanonicalName(item.Category)
+	item.Aliases = canonicalNames(item.Aliases, name)
+	codes, err := hsCodes(item.HSCodes)
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	item.HSCodes = codes
+
+	saved, err := c.repo.PutCatalogCommodity(r.Context(), item, func(commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) error {
+		return checkCatalog(replaceCommodity(commodities, item), regions)
+	})
+	if err != nil {
+		writeCatalogError(w, "commodity", err)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, saved)
+}
+
+func (c catalogAPI) deleteCommodity(w http.ResponseWriter, r *http.Request) {
+	name := validate.CanonicalName(chi.URLParam(r, "name"))
+	if err := c.repo.DeleteCatalogCommodity(r.Context(), name); err != nil {
+		writeCatalogError(w, "commodity", err)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"name": name, "deleted": true})
+}
+
+func (c catalogAPI) listRegions(w http.ResponseWriter, r *http.Request) {
+	country := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("country")))
+	items, err := c.repo.ListCatalogRegions(r.Context(), country)
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// regionCountry is the country of the region addressed by a request. A
+// region is identified by its name and ?country=, which is empty for
+// regions valid in every country.
+func regionCountry(r *http.Request) string {
+	return strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("country")))
+}
+
+func (c catalogAPI) getRegion(w http.ResponseWriter, r *http.Request) {
+	name := validate.CanonicalName(chi.URLParam(r, "name"))
+	country := regionCountry(r)
+	commodities, regions, err := c.load(r.Context())
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	catalog, err := validate.NewCatalog("", commodities, regions)
+	if err != nil {
+		httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+
+	item, ok := catalog.ResolveRegion(country, name)
+	if !ok || item.Name != name || item.Country != country {
+		writeCatalogError(w, "region", pgx.ErrNoRows)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{
+		"region":    item,
+		"ancestors": catalog.Ancestors(country, name),
+		"children":  childRegions(catalog, item),
+	})
+}
+
+// childRegions returns the regions whose parent resolves to region.
+func childRegions(catalog *validate.Catalog, region contracts.CatalogRegion) []contracts.CatalogRegion {
+	children := make([]contracts.CatalogRegion, 0)
+	for _, child := range catalog.Regions {
+		if child.Parent != region.Name {
+			continue
+		}
+		if parent, ok := catalog.ResolveRegion(child.Country, child.Parent); ok && parent.Country == region.Country {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func (c catalogAPI) putRegion(w http.ResponseWriter, r *http.Request) {
+	var item contracts.CatalogRegion
+	if err := httpx.DecodeJSON(r, &item); err != nil {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	name := validate.CanonicalName(chi.URLParam(r, "name"))
+	if item.Name != "" && validate.CanonicalName(item.Name) != name {
+		httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": "name in body does not match the path"})
+		return
+	}
+	country := strings.ToUpper(strings.TrimSpace(item.Country))
+	if query := regionCountry(r); query != "" {
+		if country != "" && country != query {
+			httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": "country in body does not match ?country="})
+			return
+		}
+		country = query
+	}
+	item.Name = name
+	item.DisplayName = strings.TrimSpace(item.DisplayName)
+	item.Country = country
+	item.Parent = validate.CanonicalName(item.Parent)
+	item.Aliases = canonicalNames(item.Aliases, name)
+	if item.Parent == "" && name != validate.RootRegion {
+		item.Parent = validate.RootRegion
+	}
+
+	saved, err := c.repo.PutCatalogRegion(r.Context(), item, func(commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) error {
+		return checkCatalog(commodities, replaceRegion(regions, item))
+	})
+	if err != nil {
+		writeCatalogError(w, "region", err)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, saved)
+}
+
+func (c catalogAPI) deleteRegion(w http.ResponseWriter, r *http.Request) {
+	name := validate.CanonicalName(chi.URLParam(r, "name"))
+	country := regionCountry(r)
+	err := c.repo.DeleteCatalogRegion(r.Context(), country, name, func(commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) error {
+		catalog, err := validate.NewCatalog("", commodities, regions)
+		if err != nil {
+			return err
+		}
+		if children := childRegions(catalog, contracts.CatalogRegion{Name: name, Country: country}); len(children) > 0 {
+			child := children[0].Name
+			if children[0].Country != "" {
+				child = children[0].Country + "/" + child
+			}
+			return catalogConflict{fmt.Errorf("region %q is the parent of %q", name, child)}
+		}
+		return nil
+	})
+	if err != nil {
+		writeCatalogError(w, "region", err)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"name": name, "country": country, "deleted": true})
+}
+
+// seedCatalog fills an empty catalog from the embedded default.
+func seedCatalog(ctx context.Context, repo *storage.Repository) error {
+	defaults, err := validate.LoadCatalog("")
+	if err != nil {
+		return err
+	}
+	seeded, err := repo.SeedCatalog(ctx, defaults.Commodities, defaults.Regions)
+	if err != nil {
+		return err
+	}
+	if seeded {
+		log.Printf("query-api seeded catalog %s: %d commodities, %d regions", defaults.Version, len(defaults.Commodities), len(defaults.Regions))
+	}
+	return nil
+}
+
+// checkCatalog reports the problem of a catalog an edit would leave as a
+// catalogConflict.
+func checkCatalog(commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) error {
+	if _, err := validate.NewCatalog("", commodities, regions); err != nil {
+		return catalogConflict{err}
+	}
+	return nil
+}
+
+func writeCatalogError(w http.ResponseWriter, kind string, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		httpx.WriteJSON(w, http.StatusNotFound, map[string]any{"error": kind + " not found"})
+		return
+	}
+	var conflict catalogConflict
+	if errors.As(err, &conflict) {
+		httpx.WriteJSON(w, http.StatusConflict, map[string]any{"error": err.Error()})
+		return
+	}
+	httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+}
+
+// canonicalNames canonicalizes aliases and drops blanks, duplicates and
+// the entry's own name.
+func canonicalNames(values []string, name string) []string {
+	seen := map[string]bool{name: true}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = validate.CanonicalName(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// hsCodes checks that every code is a Harmonized System chapter, heading
+// or subheading, or a national extension of one: 2 to 10 digits.
+func hsCodes(values []string) ([]string, error) {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ReplaceAll(strings.TrimSpace(v), ".", "")
+		if len(v) < 2 || len(v) > 10 || strings.Trim(v, "0123456789") != "" {
+			return nil, fmt.Errorf("hs code %q must be 2 to 10 digits", v)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func replaceCommodity(items []contracts.CatalogCommodity, item contracts.CatalogCommodity) []contracts.CatalogCommodity {
+	for i := range items {
+		if items[i].Name == item.Name {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func replaceRegion(items []contracts.CatalogRegion, item contracts.CatalogRegion) []contracts.CatalogRegion {
+	for i := range items {
+		if items[i].Name == item.Name && items[i].Country == item.Country {
+			items[i] = item
+			return items
+		}
+	}
+	return append(items, item)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/validate"
+)
+
+// fakeCatalog keeps the catalog in memory and runs checks against it the way
+// the repository does inside its transaction.
+type fakeCatalog struct {
+	commodities []contracts.CatalogCommodity
+	regions     []contracts.CatalogRegion
+}
+
+func (f *fakeCatalog) ListCatalogCommodities(context.Context, string) ([]contracts.CatalogCommodity, error) {
+	return append([]contracts.CatalogCommodity(nil), f.commodities...), nil
+}
+
+func (f *fakeCatalog) GetCatalogCommodity(_ context.Context, name string) (contracts.CatalogCommodity, error) {
+	for _, item := range f.commodities {
+		if item.Name == name {
+			return item, nil
+		}
+	}
+	return contracts.CatalogCommodity{}, pgx.ErrNoRows
+}
+
+func (f *fakeCatalog) PutCatalogCommodity(_ context.Context, item contracts.CatalogCommodity, check storage.CatalogCheck) (contracts.CatalogCommodity, error) {
+	commodities, regions := f.snapshot()
+	if err := check(commodities, regions); err != nil {
+		return contracts.CatalogCommodity{}, err
+	}
+	f.commodities = replaceCommodity(f.commodities, item)
+	return item, nil
+}
+
+func (f *fakeCatalog) DeleteCatalogCommodity(_ context.Context, name string) error {
+	for i, item := range f.commodities {
+		if item.Name == name {
+			f.commodities = append(f.commodities[:i], f.commodities[i+1:]...)
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (f *fakeCatalog) ListCatalogRegions(context.Context, string) ([]contracts.CatalogRegion, error) {
+	return append([]contracts.CatalogRegion(nil), f.regions...), nil
+}
+
+func (f *fakeCatalog) PutCatalogRegion(_ context.Context, item contracts.CatalogRegion, check storage.CatalogCheck) (contracts.CatalogRegion, error) {
+	commodities, regions := f.snapshot()
+	if err := check(commodities, regions); err != nil {
+		return contracts.CatalogRegion{}, err
+	}
+	f.regions = replaceRegion(f.regions, item)
+	return item, nil
+}
+
+func (f *fakeCatalog) DeleteCatalogRegion(_ context.Context, country, name string, check storage.CatalogCheck) error {
+	commodities, regions := f.snapshot()
+	if err := check(commodities, regions); err != nil {
+		return err
+	}
+	for i, item := range f.regions {
+		if item.Name == name && item.Country == country {
+			f.regions = append(f.regions[:i], f.regions[i+1:]...)
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (f *fakeCatalog) snapshot() ([]contracts.CatalogCommodity, []contracts.CatalogRegion) {
+	return append([]contracts.CatalogCommodity(nil), f.commodities...), append([]contracts.CatalogRegion(nil), f.regions...)
+}
+
+func newTestCatalog(t *testing.T) (*fakeCatalog, http.Handler) {
+	t.Helper()
+	defaults, err := validate.LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &fakeCatalog{commodities: defaults.Commodities, regions: defaults.Regions}
+	router := chi.NewRouter()
+	catalogAPI{repo: store}.routes(router)
+	return store, router
+}
+
+func TestCatalogAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"get commodity", http.MethodGet, "/v1/catalog/commodities/Insulin", "", http.StatusOK},
+		{"get missing commodity", http.MethodGet, "/v1/catalog/commodities/unobtainium", "", http.StatusNotFound},
+		{"put commodity", http.MethodPut, "/v1/catalog/commodities/cobalt", `{"display_name":"Cobalt","category":"metals","hs_codes":["8105"],"aliases":["Co"]}`, http.StatusOK},
+		{"put malformed body", http.MethodPut, "/v1/catalog/commodities/cobalt", `{"aliases":`, http.StatusBadRequest},
+		{"put mismatched name", http.MethodPut, "/v1/catalog/commodities/cobalt", `{"name":"nickel"}`, http.StatusBadRequest},
+		{"put invalid hs code", http.MethodPut, "/v1/catalog/commodities/cobalt", `{"hs_codes":["81x5"]}`, http.StatusBadRequest},
+		{"put alias of another commodity", http.MethodPut, "/v1/catalog/commodities/cobalt", `{"aliases":["petrol"]}`, http.StatusConflict},
+		{"delete missing commodity", http.MethodDelete, "/v1/catalog/commodities/unobtainium", "", http.StatusNotFound},
+		{"get region", http.MethodGet, "/v1/catalog/regions/north", "", http.StatusOK},
+		{"get missing region", http.MethodGet, "/v1/catalog/regions/atlantis", "", http.StatusNotFound},
+		{"put region", http.MethodPut, "/v1/catalog/regions/cerrado?country=BR", `{"display_name":"Cerrado","parent":"west"}`, http.StatusOK},
+		{"put mismatched country", http.MethodPut, "/v1/catalog/regions/cerrado?country=BR", `{"country":"AR"}`, http.StatusBadRequest},
+		{"put unknown parent", http.MethodPut, "/v1/catalog/regions/cerrado?country=BR", `{"parent":"atlantis"}`, http.StatusConflict},
+		{"put parent loop", http.MethodPut, "/v1/catalog/regions/global", `{"parent":"north"}`, http.StatusConflict},
+		{"delete parent region", http.MethodDelete, "/v1/catalog/regions/global", "", http.StatusConflict},
+		{"delete missing region", http.MethodDelete, "/v1/catalog/regions/atlantis?country=BR", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, router := newTestCatalog(t)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Fatalf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCatalogAPIRejectedEditIsNotWritten(t *testing.T) {
+	store, router := newTestCatalog(t)
+	before := len(store.commodities)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v1/catalog/commodities/cobalt", strings.NewReader(`{"aliases":["petrol"]}`)))
+	if rec.Code != http.StatusConflict || len(store.commodities) != before {
+		t.Fatalf("status %d with %d commodities, want 409 and %d", rec.Code, len(store.commodities), before)
+	}
+
+	// A region alias that is free when checked is taken once written, so
+	// the next edit claiming it conflicts.
+	for i, want := range []int{http.StatusOK, http.StatusConflict} {
+		name := []string{"pampas", "chaco"}[i]
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v1/catalog/regions/"+name+"?country=AR", strings.NewReader(`{"aliases":["lowlands"]}`)))
+		if rec.Code != want {
+			t.Fatalf("put %s = %d, want %d: %s", name, rec.Code, want, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/forecast"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+)
+
+type keyForecast struct {
+	Country   string `json:"country"`
+	Region    string `json:"region"`
+	Commodity string `json:"commodity"`
+	forecast.Forecast
+}
+
+// forecastKeys groups hourly buckets by key and projects every key with
+// enough history. Keys are returned highest forecast risk first.
+func forecastKeys(buckets []storage.ScoreBucket, now time.Time, opts forecast.Options, limit int) []keyForecast {
+	type group struct {
+		country, region, commodity string
+		obs                        []forecast.Observation
+	}
+
+	order := make([]string, 0)
+	groups := make(map[string]*group)
+	for _, b := range buckets {
+		key := b.Country + "|" + b.Region + "|" + b.Commodity
+		g, ok := groups[key]
+		if !ok {
+			g = &group{country: b.Country, region: b.Region, commodity: b.Commodity}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.obs = append(g.obs, forecast.Observation{At: b.BucketStart, Value: b.AvgRiskScore})
+	}
+
+	items := make([]keyForecast, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		f, err := forecast.Project(g.obs, now, opts)
+		if err != nil {
+			continue
+		}
+		items = append(items, keyForecast{Country: g.country, Region: g.region, Commodity: g.commodity, Forecast: f})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return peak(items[i].Points) > peak(items[j].Points)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+func peak(points []forecast.Point) float64 {
+	best := 0.0
+	for _, p := range points {
+		if p.Score > best {
+			best = p.Score
+		}
+	}
+	return best
+}
+
+func parseFloat(raw string, fallback, min, max float64) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || v < min || v > max {
+		return fallback
+	}
+	return v
+}
@@ -13,16 +13,27 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/alerting"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/config"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/forecast"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/httpx"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/riskconfig"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/scenario"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
 )
 
 func main() {
 	cfg := config.Load()
 
+	engineOpts, err := riskconfig.Options(cfg)
+	if err != nil {
+		log.Fatalf("query-api risk config error: %v", err)
+	}
+	commodities := engineOpts.Commodities
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -37,6 +48,11 @@ func main() {
 	}
 
 	repo := storage.NewRepository(dbPool)
+	if err := seedCatalog(ctx, repo); err != nil {
+		log.Fatalf("query-api catalog seed error: %v", err)
+	}
+	sim := &simulator{repo: repo, opts: engineOpts, policy: alerting.PolicyFromConfig(cfg)}
+	go watchPolicy(ctx, engineOpts.Policy, cfg.RiskPolicyReload)
 
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
@@ -62,6 +78,102 @@ func main() {
 		httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": events})
 	})
 
+	router.Get("/v1/risks/{id}/explain", func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		if _, err := uuid.Parse(id); err != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid risk event id"})
+			return
+		}
+
+		event, err := repo.GetRiskEvent(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				httpx.WriteJSON(w, http.StatusNotFound, map[string]any{"error": "risk event not found"})
+				return
+			}
+			httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{
+			"risk_event": event,
+			"breakdown":  event.Breakdown,
+		})
+	})
+
+	router.Get("/v1/anomalies", func(w http.ResponseWriter, r *http.Request) {
+		country := r.URL.Query().Get("country")
+		commodity := r.URL.Query().Get("commodity")
+		hours := parseBoundedInt(r.URL.Query().Get("hours"), 24, 1, 168)
+		limit := parseBoundedInt(r.URL.Query().Get("limit"), 100, 1, 500)
+
+		anomalies, err := repo.ListAnomalies(r.Context(), country, commodity, hours, limit)
+		if err != nil {
+			httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{
+			"hours": hours,
+			"items": anomalies,
+		})
+	})
+
+	router.Get("/v1/commodities/relations", func(w http.ResponseWriter, r *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{
+			"version": commodities.Version,
+			"items":   commodities.Related(r.URL.Query().Get("commodity")),
+		})
+	})
+
+	catalogAPI{repo: repo}.routes(router)
+
+	router.Get("/v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		history := parseBoundedInt(q.Get("history_hours"), 168, 12, 720)
+		limit := parseBoundedInt(q.Get("limit"), 20, 1, 100)
+		threshold := parseFloat(q.Get("threshold"), 70, 0, 100)
+
+		buckets, err := repo.ScoreHistory(r.Context(), q.Get("country"), q.Get("region"), q.Get("commodity"), history)
+		if err != nil {
+			httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+			return
+		}
+
+		items := forecastKeys(buckets, time.Now().UTC(), forecast.Options{
+			Step:      time.Hour,
+			Horizons:  forecast.DefaultHorizons,
+			Z:         forecast.Z80,
+			Threshold: threshold,
+		}, limit)
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{
+			"history_hours": history,
+			"horizon_hours": forecast.DefaultHorizons,
+			"threshold":     threshold,
+			"interval":      0.8,
+			"items":         items,
+		})
+	})
+
+	router.Post("/v1/scenarios", func(w http.ResponseWriter, r *http.Request) {
+		var req scenario.Request
+		if err := httpx.DecodeJSON(r, &req); err != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+
+		signals, err := req.Expand(time.Now().UTC())
+		if err != nil {
+			httpx.WriteJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+
+		result, err := sim.run(r.Context(), signals)
+		if err != nil {
+			httpx.WriteJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+			return
+		}
+		httpx.WriteJSON(w, http.StatusOK, result)
+	})
+
 	router.Get("/v1/alerts", func(w http.ResponseWriter, r *http.Request) {
 		status := r.URL.Query().Get("status")
 		limit := parseLimit(r.URL.Query().Get("limit"), 100)
@@ -184,7 +296,7 @@ func handleStatusUpdateError(w http.ResponseWriter, err error) {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PATCH,OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
 
 		if r.Method == http.MethodOptions {
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/alerting"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/scenario"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+)
+
+// simulator runs scenarios against a fresh engine seeded with the persisted
+// state of the keys they touch, including their zscore baselines. It only
+// reads from Postgres.
+type simulator struct {
+	repo   *storage.Repository
+	opts   risk.Options
+	policy alerting.Policy
+}
+
+func (s *simulator) run(ctx context.Context, signals []contracts.SignalEvent) (scenario.Result, error) {
+	opts := s.opts
+	// The scenario clock follows the hypothetical signal timestamps.
+	opts.Clock = risk.ClockEvent
+	opts.Shards = 1
+	opts.MaxKeys = 0
+	engine := risk.NewEngine(opts)
+
+	keys := scenario.Keys(engine, signals)
+	snapshots, err := s.repo.LoadEngineStateKeys(ctx, keys)
+	if err != nil {
+		return scenario.Result{}, err
+	}
+	engine.Restore(snapshots)
+
+	state := scenario.State{
+		Baselines:  make(map[string]float64, len(snapshots)),
+		OpenAlerts: make(map[string]time.Time),
+	}
+	for _, snap := range snapshots {
+		if snap.LastScore != nil {
+			state.Baselines[snap.Key] = *snap.LastScore
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, key := range keys {
+		country, region, commodity := risk.SplitKey(key)
+		open, err := s.repo.HasOpenAlertInCooldown(ctx, country, region, commodity, s.policy.Cooldown)
+		if err != nil {
+			return scenario.Result{}, fmt.Errorf("check open alerts for %s: %w", key, err)
+		}
+		if open {
+			state.OpenAlerts[key] = now
+		}
+	}
+
+	return scenario.Run(engine, signals, s.policy, state), nil
+}
+
+// watchPolicy reloads the weight policy scenarios score with on the same
+// interval as the risk engine, so each run uses the current snapshot.
+func watchPolicy(ctx context.Context, policy *risk.PolicyStore, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := policy.Reload()
+			if err != nil {
+				log.Printf("query-api weight policy reload error: %v", err)
+				continue
+			}
+			if changed {
+				log.Printf("query-api weight policy reloaded version=%s", policy.Current().Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/mq"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+// propagate routes the risk a scored key spills onto its neighbours to the
+// owners of the neighbouring keys. Transfers are keyed by their target on the
+// contagion topic, which is partitioned like the signals topic and consumed
+// in the same group, so they reach the instance that holds the target's
+// signals.
+func (p *pipeline) propagate(ctx context.Context, scored contracts.RiskEvent) {
+	transfers := p.engine.Spread(scored)
+	if len(transfers) == 0 {
+		return
+	}
+
+	msgs := make([]kafka.Message, 0, len(transfers))
+	for _, t := range transfers {
+		msg, err := mq.EncodeJSON(t.Target, t)
+		if err != nil {
+			log.Printf("risk-engine encode contagion %s error: %v", t.Target, err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := p.contagion.WriteMessages(ctx, msgs...); err != nil {
+		log.Printf("risk-engine publish contagion error: %v", err)
+	}
+}
+
+// receive applies a transfer routed here by propagate.
+func (p *pipeline) receive(ctx context.Context, msg kafka.Message) {
+	t, err := mq.ParseMessageJSON[risk.Transfer](msg)
+	if err != nil {
+		log.Printf("risk-engine decode contagion error: %v", err)
+		return
+	}
+
+	restoreKey(ctx, p.engine, p.repo, t.Target)
+	if scored, ok := p.engine.Receive(t); ok {
+		p.emit(ctx, scored)
+	}
+}
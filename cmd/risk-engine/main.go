@@ -3,24 +3,35 @@ package main
 import (
 	"context"
 	"errors"
+	"hash/fnv"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/segmentio/kafka-go"
 
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/config"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/httpx"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/mq"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/riskconfig"
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
 )
 
 func main() {
 	cfg := config.Load()
 
+	opts, err := riskconfig.Options(cfg)
+	if err != nil {
+		log.Fatalf("risk-engine config error: %v", err)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -32,20 +43,60 @@ func main() {
 
 	repo := storage.NewRepository(dbPool)
 
-	reader := mq.NewReader(cfg.KafkaBrokers, cfg.KafkaTopicSignals, cfg.ConsumerGroupPrefix+"-risk-engine")
+	reader := mq.NewCoReader(cfg.KafkaBrokers, []string{cfg.KafkaTopicSignals, cfg.KafkaTopicContagion}, cfg.ConsumerGroupPrefix+"-risk-engine")
 	defer reader.Close()
 
 	writer := mq.NewWriter(cfg.KafkaBrokers, cfg.KafkaTopicRisk)
 	defer writer.Close()
 
-	engine := risk.NewEngine(30 * time.Minute)
+	contagion := mq.NewWriter(cfg.KafkaBrokers, cfg.KafkaTopicContagion)
+	defer contagion.Close()
+
+	engine := risk.NewEngine(opts)
+
+	log.Printf("risk-engine consuming %s and %s and producing %s model=%s decay=%s half_life=%s window=%s history_cap=%d overrides=%d clock=%s consumers=%d", cfg.KafkaTopicSignals, cfg.KafkaTopicContagion, cfg.KafkaTopicRisk, opts.Scorer.Name(), opts.Decay.Mode, opts.Decay.HalfLife, opts.Window, opts.HistoryCap, len(opts.Overrides), opts.Clock, cfg.RiskConsumers)
+
+	log.Printf("risk-engine weight policy version=%s", opts.Policy.Current().Version)
+	log.Printf("risk-engine contagion graph version=%s edges=%d", opts.Contagion.Graph.Version, len(opts.Contagion.Graph.Edges))
+	log.Printf("risk-engine commodity graph version=%s relations=%d", opts.Commodities.Version, len(opts.Commodities.Relations))
+
+	p := &pipeline{engine: engine, repo: repo, writer: writer, contagion: contagion, contagionTopic: cfg.KafkaTopicContagion}
+	restoreState(ctx, engine, repo, cfg.RiskStateRetention)
+	// Keys whose window expired while no pod held them are drained before
+	// the sweeper can evict them with their last score still published.
+	p.rescore(ctx)
+
+	go watchPolicy(ctx, opts.Policy, cfg.RiskPolicyReload)
+	go runStateSnapshots(ctx, engine, repo, cfg.RiskSnapshotInterval, cfg.RiskStateRetention)
+	go runEvictionSweeper(ctx, engine, repo, cfg.RiskEvictionInterval)
+	go serveHTTP(ctx, cfg.HTTPAddr, engine)
+	go p.runHeartbeat(ctx, cfg.RiskHeartbeatInterval)
+
+	workers := make([]chan kafka.Message, cfg.RiskConsumers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		workers[i] = make(chan kafka.Message, 64)
+		wg.Add(1)
+		go func(in <-chan kafka.Message) {
+			defer wg.Done()
+			for msg := range in {
+				p.handle(ctx, msg)
+			}
+		}(workers[i])
+	}
 
-	log.Printf("risk-engine consuming %s and producing %s", cfg.KafkaTopicSignals, cfg.KafkaTopicRisk)
 	for {
 		msg, err := reader.ReadMessage(ctx)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				log.Println("risk-engine shutting down")
+				for _, in := range workers {
+					close(in)
+				}
+				wg.Wait()
+				flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				flushState(flushCtx, engine, repo)
+				cancel()
 				return
 			}
 			log.Printf("risk-engine read error: %v", err)
@@ -53,31 +104,169 @@ func main() {
 			continue
 		}
 
-		signal, err := mq.ParseMessageJSON[contracts.SignalEvent](msg)
-		if err != nil {
-			log.Printf("risk-engine decode signal error: %v", err)
-			continue
+		workers[workerFor(msg.Key, len(workers))] <- msg
+	}
+}
+
+// workerFor routes all messages for one key to the same worker so that
+// per-key ordering from the partition is preserved.
+func workerFor(key []byte, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(n))
+}
+
+type pipeline struct {
+	engine *risk.Engine
+	repo   *storage.Repository
+	writer *kafka.Writer
+	// contagion publishes transfers to contagionTopic, which the reader
+	// consumes alongside the signals.
+	contagion      *kafka.Writer
+	contagionTopic string
+}
+
+func (p *pipeline) handle(ctx context.Context, msg kafka.Message) {
+	if msg.Topic == p.contagionTopic {
+		p.receive(ctx, msg)
+		return
+	}
+
+	signal, err := mq.ParseMessageJSON[contracts.SignalEvent](msg)
+	if err != nil {
+		log.Printf("risk-engine decode signal error: %v", err)
+		return
+	}
+	if signal.Timestamp.IsZero() {
+		signal.Timestamp = time.Now().UTC()
+	}
+
+	restoreKey(ctx, p.engine, p.repo, signal.Key())
+	scored, err := p.engine.Process(signal)
+	if err != nil {
+		switch {
+		case errors.Is(err, risk.ErrLateSignal):
+			log.Printf("risk-engine dropped late signal %s: %v", signal.ID, err)
+		case errors.Is(err, risk.ErrDuplicateSignal):
+			log.Printf("risk-engine ignored duplicate signal %s", signal.ID)
+		default:
+			log.Printf("risk-engine process error: %v", err)
 		}
-		if signal.Timestamp.IsZero() {
-			signal.Timestamp = time.Now().UTC()
+		return
+	}
+
+	p.emit(ctx, scored)
+	p.propagate(ctx, scored)
+}
+
+func (p *pipeline) runHeartbeat(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.rescore(ctx)
 		}
+	}
+}
 
-		scored := engine.Process(signal)
+func (p *pipeline) rescore(ctx context.Context) {
+	for _, scored := range p.engine.Rescore(time.Now().UTC()) {
+		p.emit(ctx, scored)
+		p.propagate(ctx, scored)
+	}
+}
+
+func (p *pipeline) emit(ctx context.Context, scored contracts.RiskEvent) {
+	if err := p.repo.InsertRiskEvent(ctx, scored); err != nil {
+		log.Printf("risk-engine store risk event error: %v", err)
+	}
 
-		if err := repo.InsertRiskEvent(ctx, scored); err != nil {
-			log.Printf("risk-engine store risk event error: %v", err)
+	if err := mq.PublishJSON(ctx, p.writer, scored.Country+"|"+scored.Commodity, scored); err != nil {
+		var temporary kafka.Error
+		if errors.As(err, &temporary) {
+			log.Printf("risk-engine kafka temporary error: %v", temporary)
+		} else {
+			log.Printf("risk-engine publish error: %v", err)
 		}
+		return
+	}
+
+	log.Printf("risk-event %s %s/%s score=%.2f trigger=%s", scored.ID, scored.Country, scored.Commodity, scored.RiskScore, scored.Trigger)
+}
 
-		if err := mq.PublishJSON(ctx, writer, scored.Country+"|"+scored.Commodity, scored); err != nil {
-			var temporary kafka.Error
-			if errors.As(err, &temporary) {
-				log.Printf("risk-engine kafka temporary error: %v", temporary)
-			} else {
-				log.Printf("risk-engine publish error: %v", err)
+func serveHTTP(ctx context.Context, addr string, engine *risk.Engine) {
+	router := chi.NewRouter()
+	router.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{"ok": true, "service": "risk-engine"})
+	})
+	router.Get("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{
+			"engine":          engine.Stats(),
+			"unknown_metrics": engine.Normalizer().UnknownMetrics(),
+			"policy_version":  engine.Policy().Current().Version,
+		})
+	})
+	router.Get("/v1/policy", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, engine.Policy().Current())
+	})
+	router.Get("/v1/actions/rules", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": engine.Actions().Rules()})
+	})
+	router.Get("/v1/contagion", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, engine.Contagion())
+	})
+	router.Get("/v1/normalization", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": engine.Normalizer().Profiles()})
+	})
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("risk-engine metrics listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("risk-engine http server error: %v", err)
+	}
+}
+
+func watchPolicy(ctx context.Context, policy *risk.PolicyStore, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := policy.Reload()
+			if err != nil {
+				log.Printf("risk-engine weight policy reload error: %v", err)
+				continue
+			}
+			if changed {
+				log.Printf("risk-engine weight policy reloaded version=%s", policy.Current().Version)
 			}
-			continue
 		}
-
-		log.Printf("risk-event %s %s/%s score=%.2f", scored.ID, scored.Country, scored.Commodity, scored.RiskScore)
 	}
 }
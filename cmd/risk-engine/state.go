@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/storage"
+)
+
+// restoreState loads every snapshot kept within the retention at startup,
+// so that keys which stay quiet after a restart are still rescored by the
+// heartbeat and decay. Keys whose partition another pod owns are loaded too;
+// they are released once that pod flushes newer state for them.
+func restoreState(ctx context.Context, engine *risk.Engine, repo *storage.Repository, retention time.Duration) {
+	var since time.Time
+	if retention > 0 {
+		since = time.Now().UTC().Add(-retention)
+	}
+
+	snapshots, err := repo.LoadEngineState(ctx, since)
+	if err != nil {
+		log.Printf("risk-engine restore state error: %v", err)
+		return
+	}
+	log.Printf("risk-engine restored %d keys", engine.Resume(snapshots))
+}
+
+// restoreKey loads the persisted state of a key that is not in memory before
+// its signal is processed. After the startup restore this only happens for
+// keys that were evicted or released, or that another pod created since.
+func restoreKey(ctx context.Context, engine *risk.Engine, repo *storage.Repository, key string) {
+	if engine.Has(key) {
+		return
+	}
+
+	snapshots, err := repo.LoadEngineStateKeys(ctx, []string{key})
+	if err != nil {
+		log.Printf("risk-engine restore state %s error: %v", key, err)
+		return
+	}
+	engine.Restore(snapshots)
+}
+
+// releaseStale drops the keys another pod has processed since this one last
+// did, so they are restored from the newer snapshot if their partition comes
+// back here, and are not rescored from a stale window in the meantime. Only
+// snapshots written after since are checked.
+func releaseStale(ctx context.Context, engine *risk.Engine, repo *storage.Repository, since time.Time) {
+	versions, err := repo.EngineStateChangedSince(ctx, since)
+	if err != nil {
+		log.Printf("risk-engine state versions error: %v", err)
+		return
+	}
+	if released := engine.Release(versions); released > 0 {
+		log.Printf("risk-engine released %d keys updated by another instance", released)
+	}
+}
+
+func runStateSnapshots(ctx context.Context, engine *risk.Engine, repo *storage.Repository, interval, retention time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	checked := time.Now().UTC()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			flushState(ctx, engine, repo)
+			// A snapshot carries the time its key was last processed, which
+			// is up to one interval before another pod writes it, so the
+			// check overlaps the previous one.
+			now := time.Now().UTC()
+			releaseStale(ctx, engine, repo, checked.Add(-2*interval))
+			checked = now
+			if retention > 0 {
+				if _, err := repo.PruneEngineState(ctx, now.Add(-retention)); err != nil {
+					log.Printf("risk-engine prune state error: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func flushState(ctx context.Context, engine *risk.Engine, repo *storage.Repository) {
+	snapshots := engine.DirtySnapshots()
+	if len(snapshots) == 0 {
+		return
+	}
+
+	if err := repo.SaveEngineState(ctx, snapshots); err != nil {
+		engine.MarkDirty(snapshots)
+		log.Printf("risk-engine save state error: %v", err)
+	}
+}
+
+// runEvictionSweeper flushes dirty keys before each sweep so that keys idle
+// since the last snapshot can be evicted without losing their state.
+func runEvictionSweeper(ctx context.Context, engine *risk.Engine, repo *storage.Repository, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			flushState(ctx, engine, repo)
+			if evicted := engine.EvictIdle(time.Now().UTC()); evicted > 0 {
+				stats := engine.Stats()
+				log.Printf("risk-engine evicted %d idle keys keys=%d evictions_total=%d", evicted, stats.Keys, stats.Evictions)
+			}
+		}
+	}
+}
@@ -0,0 +1,334 @@
+// Package ais turns AIS vessel positions into shipping_lane signals: it
+// decodes NMEA sentences and decoded AIS JSON, tracks vessels through
+// configured chokepoints and reports transit counts and dwell anomalies.
+package ais
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Position is one vessel position report.
+type Position struct {
+	MMSI int
+	Lat  float64
+	Lon  float64
+	// SOG is the speed over ground in knots; negative when not available.
+	SOG float64
+	At  time.Time
+}
+
+// ErrSkip is returned for lines that are valid but carry no position, such
+// as static data messages or the first fragment of a multi-part sentence.
+var ErrSkip = errors.New("no position")
+
+// Decoder parses NMEA AIVDM/AIVDO sentences, optionally preceded by an
+// NMEA 4.10 tag block, and JSON objects from AIS decoders. It keeps partial
+// multi-fragment sentences between calls and is not safe for concurrent
+// use.
+type Decoder struct {
+	fragments map[string]*fragmentSet
+}
+
+type fragmentSet struct {
+	parts []string
+	fill  int
+	seen  int
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{fragments: make(map[string]*fragmentSet)}
+}
+
+// Decode parses one line. received is used as the position time when the
+// line does not carry one.
+func (d *Decoder) Decode(line string, received time.Time) (Position, error) {
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "":
+		return Position{}, ErrSkip
+	case strings.HasPrefix(line, "{"):
+		return decodeJSON(line, received)
+	case strings.HasPrefix(line, "\\"):
+		end := strings.Index(line[1:], "\\")
+		if end < 0 {
+			return Position{}, fmt.Errorf("unterminated tag block")
+		}
+		if at, ok := tagBlockTime(line[1 : end+1]); ok {
+			received = at
+		}
+		return d.decodeSentence(line[end+2:], received)
+	default:
+		return d.decodeSentence(line, received)
+	}
+}
+
+// tagBlockTime reads the c: (Unix time) field of a tag block.
+func tagBlockTime(block string) (time.Time, bool) {
+	if i := strings.LastIndex(block, "*"); i >= 0 {
+		block = block[:i]
+	}
+	for _, field := range strings.Split(block, ",") {
+		if !strings.HasPrefix(field, "c:") {
+			continue
+		}
+		secs, err := strconv.ParseInt(field[2:], 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		// Some receivers write milliseconds.
+		if secs > 1e11 {
+			return time.UnixMilli(secs).UTC(), true
+		}
+		return time.Unix(secs, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+func (d *Decoder) decodeSentence(sentence string, received time.Time) (Position, error) {
+	if !strings.HasPrefix(sentence, "!") {
+		return Position{}, fmt.Errorf("not an AIS sentence")
+	}
+	star := strings.LastIndex(sentence, "*")
+	if star < 0 || len(sentence) < star+3 {
+		return Position{}, fmt.Errorf("missing checksum")
+	}
+	want, err := strconv.ParseUint(sentence[star+1:star+3], 16, 8)
+	if err != nil {
+		return Position{}, fmt.Errorf("bad checksum: %w", err)
+	}
+	var sum byte
+	for i := 1; i < star; i++ {
+		sum ^= sentence[i]
+	}
+	if sum != byte(want) {
+		return Position{}, fmt.Errorf("checksum mismatch")
+	}
+
+	fields := strings.Split(sentence[1:star], ",")
+	if len(fields) < 7 || !strings.HasSuffix(fields[0], "VDM") && !strings.HasSuffix(fields[0], "VDO") {
+		return Position{}, ErrSkip
+	}
+	count, err1 := strconv.Atoi(fields[1])
+	number, err2 := strconv.Atoi(fields[2])
+	fill, err3 := strconv.Atoi(fields[6])
+	if err1 != nil || err2 != nil || err3 != nil || count < 1 || number < 1 || number > count {
+		return Position{}, fmt.Errorf("bad fragment header")
+	}
+
+	payload := fields[5]
+	if count > 1 {
+		key := fields[3] + "|" + fields[4]
+		set, ok := d.fragments[key]
+		if !ok || len(set.parts) != count || number == 1 {
+			set = &fragmentSet{parts: make([]string, count)}
+			d.fragments[key] = set
+		}
+		if set.parts[number-1] == "" {
+			set.seen++
+		}
+		set.parts[number-1] = payload
+		if number == count {
+			set.fill = fill
+		}
+		if set.seen < count {
+			return Position{}, ErrSkip
+		}
+		delete(d.fragments, key)
+		payload = strings.Join(set.parts, "")
+		fill = set.fill
+	}
+
+	bits, err := unarmor(payload, fill)
+	if err != nil {
+		return Position{}, err
+	}
+	return decodePosition(bits, received)
+}
+
+// unarmor expands the 6-bit ASCII payload into one bool per bit.
+func unarmor(payload string, fill int) ([]bool, error) {
+	bits := make([]bool, 0, len(payload)*6)
+	for i := 0; i < len(payload); i++ {
+		// The armour uses '0'..'W' and '`'..'w'; the characters between
+		// them encode nothing.
+		c := payload[i]
+		if c < '0' || c > 'w' || (c > 'W' && c < '`') {
+			return nil, fmt.Errorf("invalid payload character %q", c)
+		}
+		v := int(c) - 48
+		if v > 40 {
+			v -= 8
+		}
+		for b := 5; b >= 0; b-- {
+			bits = append(bits, v&(1<<b) != 0)
+		}
+	}
+	if fill > 0 && fill <= len(bits) {
+		bits = bits[:len(bits)-fill]
+	}
+	return bits, nil
+}
+
+func uintAt(bits []bool, start, width int) (uint64, bool) {
+	if start+width > len(bits) {
+		return 0, false
+	}
+	var v uint64
+	for _, b := range bits[start : start+width] {
+		v <<= 1
+		if b {
+			v |= 1
+		}
+	}
+	return v, true
+}
+
+func intAt(bits []bool, start, width int) (int64, bool) {
+	u, ok := uintAt(bits, start, width)
+	if !ok {
+		return 0, false
+	}
+	if u&(1<<(width-1)) != 0 {
+		return int64(u) - int64(1)<<width, true
+	}
+	return int64(u), true
+}
+
+// layout gives the bit offsets of the fields a message type carries.
+type layout struct {
+	sog, sogWidth, sogNA int
+	sogScale             float64
+	lon, lonWidth        int
+	lat, latWidth        int
+	coordScale           float64
+}
+
+var layouts = map[uint64]layout{
+	// Class A position reports.
+	1: {sog: 50, sogWidth: 10, sogNA: 1023, sogScale: 10, lon: 61, lonWidth: 28, lat: 89, latWidth: 27, coordScale: 600000},
+	2: {sog: 50, sogWidth: 10, sogNA: 1023, sogScale: 10, lon: 61, lonWidth: 28, lat: 89, latWidth: 27, coordScale: 600000},
+	3: {sog: 50, sogWidth: 10, sogNA: 1023, sogScale: 10, lon: 61, lonWidth: 28, lat: 89, latWidth: 27, coordScale: 600000},
+	// Class B position reports.
+	18: {sog: 46, sogWidth: 10, sogNA: 1023, sogScale: 10, lon: 57, lonWidth: 28, lat: 85, latWidth: 27, coordScale: 600000},
+	19: {sog: 46, sogWidth: 10, sogNA: 1023, sogScale: 10, lon: 57, lonWidth: 28, lat: 85, latWidth: 27, coordScale: 600000},
+	// Long range broadcast.
+	27: {sog: 79, sogWidth: 6, sogNA: 63, sogScale: 1, lon: 44, lonWidth: 18, lat: 62, latWidth: 17, coordScale: 600},
+}
+
+func decodePosition(bits []bool, received time.Time) (Position, error) {
+	msgType, ok := uintAt(bits, 0, 6)
+	if !ok {
+		return Position{}, fmt.Errorf("empty payload")
+	}
+	l, ok := layouts[msgType]
+	if !ok {
+		return Position{}, ErrSkip
+	}
+
+	mmsi, ok1 := uintAt(bits, 8, 30)
+	lon, ok2 := intAt(bits, l.lon, l.lonWidth)
+	lat, ok3 := intAt(bits, l.lat, l.latWidth)
+	sog, ok4 := uintAt(bits, l.sog, l.sogWidth)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return Position{}, fmt.Errorf("message %d is too short", msgType)
+	}
+
+	p := Position{
+		MMSI: int(mmsi),
+		Lat:  float64(lat) / l.coordScale,
+		Lon:  float64(lon) / l.coordScale,
+		SOG:  -1,
+		At:   received,
+	}
+	if int(sog) != l.sogNA {
+		p.SOG = float64(sog) / l.sogScale
+	}
+	if err := p.check(); err != nil {
+		return Position{}, err
+	}
+	return p, nil
+}
+
+// check rejects the "not available" coordinates (91, 181) and anything
+// else off the globe.
+func (p Position) check() error {
+	if p.MMSI <= 0 {
+		return fmt.Errorf("missing mmsi")
+	}
+	if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+		return ErrSkip
+	}
+	return nil
+}
+
+// decodeJSON accepts the field names used by common AIS decoders and
+// aggregators.
+func decodeJSON(line string, received time.Time) (Position, error) {
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return Position{}, fmt.Errorf("parse json: %w", err)
+	}
+
+	number := func(keys ...string) (float64, bool) {
+		for _, key := range keys {
+			switch v := raw[key].(type) {
+			case float64:
+				return v, true
+			case string:
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					return f, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	mmsi, ok := number("mmsi", "MMSI", "userid")
+	if !ok {
+		return Position{}, ErrSkip
+	}
+	lat, okLat := number("lat", "latitude", "LAT", "y")
+	lon, okLon := number("lon", "lng", "longitude", "LON", "x")
+	if !okLat || !okLon {
+		return Position{}, ErrSkip
+	}
+
+	p := Position{MMSI: int(mmsi), Lat: lat, Lon: lon, SOG: -1, At: received}
+	if sog, ok := number("sog", "speed", "SOG", "speed_over_ground"); ok && sog < 102.3 {
+		p.SOG = sog
+	}
+	for _, key := range []string{"timestamp", "time", "received_at", "ts"} {
+		if v, ok := raw[key]; ok {
+			if at, ok := jsonTime(v); ok {
+				p.At = at
+				break
+			}
+		}
+	}
+	if err := p.check(); err != nil {
+		return Position{}, err
+	}
+	return p, nil
+}
+
+func jsonTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+			if at, err := time.Parse(layout, t); err == nil {
+				return at.UTC(), true
+			}
+		}
+	case float64:
+		if t > 1e11 {
+			return time.UnixMilli(int64(t)).UTC(), true
+		}
+		return time.Unix(int64(t), 0).UTC(), true
+	}
+	return time.Time{}, false
+}
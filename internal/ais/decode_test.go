@@ -0,0 +1,117 @@
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// sentence wraps an AIVDM body with its checksum.
+func sentence(body string) string {
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	return fmt.Sprintf("!%s*%02X", body, sum)
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-5 }
+
+func TestDecodePositions(t *testing.T) {
+	received := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		line     string
+		mmsi     int
+		lat, lon float64
+		sog      float64
+	}{
+		{"type 1", "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A", 371798000, 48.38163, -123.39538, 12.3},
+		{"type 18", "!AIVDM,1,1,,A,B5NJ;PP005l4ot5Isbl03wsUkP06,0*76", 367430530, 37.785035, -122.26732, 0},
+		{"type 27", "!AIVDM,1,1,,B,KC5E2b@U19PFdLbL,0*00", 206914217, 4.84, 137.02333, 57},
+		{"json", `{"mmsi":"211234560","lat":53.5,"lon":9.9,"sog":4.2}`, 211234560, 53.5, 9.9, 4.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewDecoder().Decode(tt.line, received)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.MMSI != tt.mmsi || !near(p.Lat, tt.lat) || !near(p.Lon, tt.lon) || !near(p.SOG, tt.sog) {
+				t.Fatalf("decoded %+v", p)
+			}
+		})
+	}
+}
+
+func TestDecodeTagBlockTime(t *testing.T) {
+	line := `\s:station,c:1772445600*00\!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4A`
+	p, err := NewDecoder().Decode(line, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(1772445600, 0).UTC(); !p.At.Equal(want) {
+		t.Fatalf("at = %s, want %s", p.At, want)
+	}
+}
+
+func TestDecodeMultipartStatic(t *testing.T) {
+	d := NewDecoder()
+	parts := []string{
+		"!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C",
+		"!AIVDM,2,2,1,A,88888888880,2*25",
+	}
+	for i, line := range parts {
+		if _, err := d.Decode(line, time.Time{}); !errors.Is(err, ErrSkip) {
+			t.Fatalf("part %d: err = %v, want ErrSkip", i+1, err)
+		}
+	}
+	// Type 5 carries no position, but both fragments were consumed.
+	if len(d.fragments) != 0 {
+		t.Fatalf("%d fragment sets left over", len(d.fragments))
+	}
+
+	bits, err := unarmor("55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp888888888880", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgType, _ := uintAt(bits, 0, 6); msgType != 5 || len(bits) != 424 {
+		t.Fatalf("type %d with %d bits, want type 5 with 424", msgType, len(bits))
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		skip bool
+	}{
+		{"checksum", "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0*4B", false},
+		{"missing checksum", "!AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0", false},
+		{"fragment header", sentence("AIVDM,1,2,,A,15RTgt0PAso;90TKcjM8h6g208CQ,0"), false},
+		{"not ais", "$GPGGA,123519,4807.038,N", false},
+		{"other talker sentence", sentence("AIVDX,1,1"), true},
+		{"unknown type", sentence("AIVDM,1,1,,A,P5RTgt0PAso;90TKcjM8h6g208CQ,0"), true},
+		{"json without position", `{"mmsi":211234560}`, true},
+	}
+	for c := byte('X'); c <= '_'; c++ {
+		tests = append(tests, struct {
+			name string
+			line string
+			skip bool
+		}{fmt.Sprintf("character %q", c), sentence("AIVDM,1,1,,A,15RTgt0PAso;90TKcjM8h6g20" + string(c) + "CQ,0"), false})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDecoder().Decode(tt.line, time.Time{})
+			if err == nil {
+				t.Fatal("decoded an invalid line")
+			}
+			if errors.Is(err, ErrSkip) != tt.skip {
+				t.Fatalf("err = %v, skip = %t", err, tt.skip)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ais
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed defaults/lanes.json
+var defaultLanes []byte
+
+// Lane is a chokepoint watched for transits. Polygon lists [lat, lon]
+// vertices; the ring is closed implicitly.
+type Lane struct {
+	Name        string       `json:"name"`
+	Country     string       `json:"country"`
+	Region      string       `json:"region"`
+	Commodities []string     `json:"commodities"`
+	Polygon     [][2]float64 `json:"polygon"`
+	// ExpectedTransitsPerHour is the normal traffic; zero disables the
+	// transit shortfall signal.
+	ExpectedTransitsPerHour float64 `json:"expected_transits_per_hour"`
+	// MaxDwellMinutes is the longest a vessel normally stays inside; zero
+	// disables the dwell anomaly signal.
+	MaxDwellMinutes float64 `json:"max_dwell_minutes"`
+}
+
+type lanesFile struct {
+	Lanes []Lane `json:"lanes"`
+}
+
+// LoadLanes reads lanes from path, or the embedded default when path is
+// empty.
+func LoadLanes(path string) ([]Lane, error) {
+	body := defaultLanes
+	if strings.TrimSpace(path) != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read lanes: %w", err)
+		}
+		body = raw
+	}
+
+	var f lanesFile
+	if err := json.Unmarshal(body, &f); err != nil {
+		return nil, fmt.Errorf("parse lanes: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i, lane := range f.Lanes {
+		if strings.TrimSpace(lane.Name) == "" {
+			return nil, fmt.Errorf("lane %d: name is required", i)
+		}
+		if seen[lane.Name] {
+			return nil, fmt.Errorf("lane %q is listed twice", lane.Name)
+		}
+		seen[lane.Name] = true
+		if len(lane.Polygon) < 3 {
+			return nil, fmt.Errorf("lane %q: polygon needs at least 3 points", lane.Name)
+		}
+		if len(lane.Commodities) == 0 {
+			return nil, fmt.Errorf("lane %q: commodities are required", lane.Name)
+		}
+		if lane.ExpectedTransitsPerHour < 0 || lane.MaxDwellMinutes < 0 {
+			return nil, fmt.Errorf("lane %q: thresholds must not be negative", lane.Name)
+		}
+	}
+	return f.Lanes, nil
+}
+
+// Contains reports whether a position falls inside the lane polygon, by ray
+// casting. Lanes are small enough that treating degrees as planar is fine.
+func (l Lane) Contains(lat, lon float64) bool {
+	inside := false
+	n := len(l.Polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		ai, aj := l.Polygon[i], l.Polygon[j]
+		if (ai[1] > lon) != (aj[1] > lon) &&
+			lat < (aj[0]-ai[0])*(lon-ai[1])/(aj[1]-ai[1])+ai[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,108 @@
+package ais
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxLine bounds one NMEA or JSON line; AIS sentences are at most 82
+// characters but JSON decoders add plenty of fields.
+const maxLine = 64 * 1024
+
+// Read streams lines from source to handle until ctx is done. Source is
+// tcp://host:port (reconnects with backoff), udp://host:port (listens for
+// datagrams), or file:///path and a plain path (read once to the end).
+func Read(ctx context.Context, source string, handle func(line string)) error {
+	switch {
+	case strings.HasPrefix(source, "tcp://"):
+		readTCP(ctx, strings.TrimPrefix(source, "tcp://"), handle)
+		return nil
+	case strings.HasPrefix(source, "udp://"):
+		return readUDP(ctx, strings.TrimPrefix(source, "udp://"), handle)
+	default:
+		return readFile(ctx, strings.TrimPrefix(source, "file://"), handle)
+	}
+}
+
+func readTCP(ctx context.Context, addr string, handle func(string)) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			log.Printf("ais connected to %s", addr)
+			backoff = time.Second
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			err = scanLines(ctx, conn, handle)
+			stop()
+			conn.Close()
+			if err == nil {
+				err = errors.New("connection closed")
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("ais source %s: %v; reconnecting in %s", addr, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, time.Minute)
+	}
+}
+
+func readUDP(ctx context.Context, addr string, handle func(string)) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", addr, err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	buf := make([]byte, maxLine)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("read %s: %w", addr, err)
+		}
+		// A datagram may carry several sentences.
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			handle(line)
+		}
+	}
+}
+
+func readFile(ctx context.Context, path string, handle func(string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	return scanLines(ctx, f, handle)
+}
+
+func scanLines(ctx context.Context, r io.Reader, handle func(string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxLine)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handle(scanner.Text())
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,288 @@
+package ais
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const (
+	// MetricTransitShortfall is how far, in percent, the transits of a
+	// window fell below the lane's expected traffic.
+	MetricTransitShortfall = "transit_shortfall_percent"
+	// MetricDwellAnomalies is the number of vessels that stayed in the
+	// lane longer than its max dwell.
+	MetricDwellAnomalies = "dwell_anomaly_vessels"
+)
+
+// idNamespace derives signal IDs from the lane, metric, commodity and
+// window, so replaying a feed yields the same IDs and ingest drops the
+// repeats.
+var idNamespace = uuid.MustParse("0b6e2f4c-8d1a-4c53-9f27-5e3a7d9c1b80")
+
+// ErrLatePosition is returned for a position whose window has closed.
+var ErrLatePosition = errors.New("position is older than the open window")
+
+// maxSkippedWindows bounds how many empty windows are closed one by one
+// when the feed jumps ahead in time.
+const maxSkippedWindows = 48
+
+type Options struct {
+	// Window is the aggregation period; transits are counted per window.
+	Window time.Duration
+	// StaleAfter drops a vessel that has not reported for this long without
+	// counting a transit, since it was most likely lost by the receiver.
+	StaleAfter time.Duration
+	// AllowedLateness keeps a window open for this long after its end, so
+	// positions that trail the newest one or the wall clock, as relayed and
+	// satellite reports do, still count in their own window.
+	AllowedLateness time.Duration
+}
+
+// LaneStatus is the last closed window of a lane.
+type LaneStatus struct {
+	Lane             string    `json:"lane"`
+	WindowEnd        time.Time `json:"window_end,omitzero"`
+	Transits         int       `json:"transits"`
+	ExpectedTransits float64   `json:"expected_transits"`
+	Vessels          int       `json:"vessels"`
+	DwellAnomalies   int       `json:"dwell_anomalies"`
+	Positions        int       `json:"positions"`
+}
+
+type visit struct {
+	entered  time.Time
+	lastSeen time.Time
+}
+
+// laneState counts transits and positions by the start of their window, so
+// positions of a window that is still open count there even when later
+// windows have positions too.
+type laneState struct {
+	lane      Lane
+	inside    map[int]*visit
+	transits  map[time.Time]int
+	positions map[time.Time]int
+	last      LaneStatus
+}
+
+// Tracker follows vessels through lanes in event time: windows close once
+// the newest position, or the time passed to Advance, is AllowedLateness
+// past their end. It is safe for concurrent use.
+type Tracker struct {
+	opts Options
+
+	mu          sync.Mutex
+	lanes       []*laneState
+	windowStart time.Time
+}
+
+func NewTracker(lanes []Lane, opts Options) *Tracker {
+	if opts.Window <= 0 {
+		opts.Window = time.Hour
+	}
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = 2 * opts.Window
+	}
+	t := &Tracker{opts: opts}
+	for _, lane := range lanes {
+		t.lanes = append(t.lanes, &laneState{
+			lane:      lane,
+			inside:    make(map[int]*visit),
+			transits:  make(map[time.Time]int),
+			positions: make(map[time.Time]int),
+			last:      LaneStatus{Lane: lane.Name},
+		})
+	}
+	return t
+}
+
+// Observe records a position and returns the signals of any windows it
+// closed. A vessel leaving a lane counts as one transit. A position whose
+// window has already closed returns ErrLatePosition.
+func (t *Tracker) Observe(p Position) ([]contracts.SignalEvent, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	window := p.At.Truncate(t.opts.Window)
+	if !t.windowStart.IsZero() && window.Before(t.windowStart) {
+		return nil, ErrLatePosition
+	}
+	out := t.advance(p.At.Add(-t.opts.AllowedLateness))
+
+	for _, state := range t.lanes {
+		v, tracked := state.inside[p.MMSI]
+		switch in := state.lane.Contains(p.Lat, p.Lon); {
+		case in && tracked:
+			v.lastSeen = p.At
+		case in:
+			state.inside[p.MMSI] = &visit{entered: p.At, lastSeen: p.At}
+		case tracked:
+			delete(state.inside, p.MMSI)
+			state.transits[window]++
+		default:
+			continue
+		}
+		state.positions[window]++
+	}
+	return out, nil
+}
+
+// Advance closes the windows that ended AllowedLateness before now, so lanes
+// that went quiet still report.
+func (t *Tracker) Advance(now time.Time) []contracts.SignalEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.windowStart.IsZero() {
+		return nil
+	}
+	return t.advance(now.Add(-t.opts.AllowedLateness))
+}
+
+// advance closes every window that ended at or before now.
+func (t *Tracker) advance(now time.Time) []contracts.SignalEvent {
+	if t.windowStart.IsZero() {
+		t.windowStart = now.Truncate(t.opts.Window)
+		return nil
+	}
+
+	var out []contracts.SignalEvent
+	for closed := 0; !now.Before(t.windowStart.Add(t.opts.Window)); closed++ {
+		start, end := t.windowStart, t.windowStart.Add(t.opts.Window)
+		out = append(out, t.closeWindow(start, end)...)
+		t.windowStart = end
+		if closed == maxSkippedWindows {
+			t.windowStart = now.Truncate(t.opts.Window)
+			t.dropBefore(t.windowStart)
+		}
+	}
+	return out
+}
+
+// dropBefore forgets the counts of windows skipped without being closed.
+func (t *Tracker) dropBefore(start time.Time) {
+	for _, state := range t.lanes {
+		for window := range state.positions {
+			if window.Before(start) {
+				delete(state.positions, window)
+				delete(state.transits, window)
+			}
+		}
+	}
+}
+
+func (t *Tracker) closeWindow(start, end time.Time) []contracts.SignalEvent {
+	var out []contracts.SignalEvent
+	for _, state := range t.lanes {
+		for mmsi, v := range state.inside {
+			if end.Sub(v.lastSeen) > t.opts.StaleAfter {
+				delete(state.inside, mmsi)
+			}
+		}
+
+		lane := state.lane
+		maxDwell := time.Duration(lane.MaxDwellMinutes * float64(time.Minute))
+		anomalies := 0
+		if maxDwell > 0 {
+			for _, v := range state.inside {
+				if end.Sub(v.entered) > maxDwell {
+					anomalies++
+				}
+			}
+		}
+		expected := lane.ExpectedTransitsPerHour * t.opts.Window.Hours()
+
+		positions := state.positions[start]
+		status := LaneStatus{
+			Lane:             lane.Name,
+			WindowEnd:        end,
+			Transits:         state.transits[start],
+			ExpectedTransits: expected,
+			Vessels:          len(state.inside),
+			DwellAnomalies:   anomalies,
+			Positions:        positions,
+		}
+		delete(state.transits, start)
+		delete(state.positions, start)
+
+		// A window without a single position says more about receiver
+		// coverage than about the lane, so it is not reported as an empty
+		// lane.
+		if positions == 0 {
+			continue
+		}
+		state.last = status
+
+		if expected > 0 {
+			shortfall := math.Max(0, 1-float64(status.Transits)/expected)
+			out = append(out, laneSignals(lane, MetricTransitShortfall, end, shortfall*100, severity(shortfall), status)...)
+		}
+		if maxDwell > 0 {
+			share := float64(anomalies) / math.Max(1, float64(status.Vessels))
+			out = append(out, laneSignals(lane, MetricDwellAnomalies, end, float64(anomalies), severity(share), status)...)
+		}
+	}
+	return out
+}
+
+// laneSignals emits one signal per lane commodity. Confidence grows with
+// the number of vessels seen, since a handful of reports may just be gaps
+// in receiver coverage.
+func laneSignals(lane Lane, metric string, end time.Time, value float64, sev int, status LaneStatus) []contracts.SignalEvent {
+	observed := status.Transits + status.Vessels
+	confidence := 0.5 + 0.4*math.Min(1, float64(observed)/10)
+
+	region := lane.Region
+	if region == "" {
+		region = "coastal"
+	}
+	out := make([]contracts.SignalEvent, 0, len(lane.Commodities))
+	for _, commodity := range lane.Commodities {
+		key := lane.Name + "|" + metric + "|" + commodity + "|" + end.UTC().Format(time.RFC3339)
+		out = append(out, contracts.SignalEvent{
+			ID:          uuid.NewSHA1(idNamespace, []byte(key)).String(),
+			Timestamp:   end.UTC(),
+			Source:      contracts.SourceShippingLane,
+			Country:     lane.Country,
+			Region:      region,
+			Commodity:   commodity,
+			MetricName:  metric,
+			MetricValue: math.Round(value*100) / 100,
+			Severity:    sev,
+			Confidence:  math.Round(confidence*100) / 100,
+			Metadata: map[string]string{
+				"lane":              lane.Name,
+				"transits":          strconv.Itoa(status.Transits),
+				"expected_transits": strconv.FormatFloat(status.ExpectedTransits, 'f', -1, 64),
+				"vessels":           strconv.Itoa(status.Vessels),
+				"dwell_anomalies":   strconv.Itoa(status.DwellAnomalies),
+			},
+		})
+	}
+	return out
+}
+
+// severity maps a 0..1 share onto the 1..10 signal scale.
+func severity(share float64) int {
+	return 1 + int(math.Round(9*math.Min(1, math.Max(0, share))))
+}
+
+// Status reports the last closed window of every lane, ordered by name.
+func (t *Tracker) Status() []LaneStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]LaneStatus, 0, len(t.lanes))
+	for _, state := range t.lanes {
+		out = append(out, state.last)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Lane < out[j].Lane })
+	return out
+}
@@ -0,0 +1,150 @@
+package ais
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+var strait = Lane{
+	Name:                    "test-strait",
+	Country:                 "EG",
+	Commodities:             []string{"crude_oil"},
+	Polygon:                 [][2]float64{{30, 32}, {30, 33}, {31, 33}, {31, 32}},
+	ExpectedTransitsPerHour: 2,
+	MaxDwellMinutes:         30,
+}
+
+func TestLaneContains(t *testing.T) {
+	if !strait.Contains(30.5, 32.5) {
+		t.Fatal("centre is outside")
+	}
+	for _, p := range [][2]float64{{29.9, 32.5}, {30.5, 33.1}, {40, 40}} {
+		if strait.Contains(p[0], p[1]) {
+			t.Fatalf("%v is inside", p)
+		}
+	}
+}
+
+func byMetric(signals []contracts.SignalEvent) map[string]contracts.SignalEvent {
+	out := make(map[string]contracts.SignalEvent, len(signals))
+	for _, s := range signals {
+		out[s.MetricName] = s
+	}
+	return out
+}
+
+func observe(t *testing.T, tr *Tracker, p Position) []contracts.SignalEvent {
+	t.Helper()
+	out, err := tr.Observe(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestTrackerWindow(t *testing.T) {
+	tr := NewTracker([]Lane{strait}, Options{Window: time.Hour})
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+
+	// Vessel 1 passes through; vessel 2 enters and stays.
+	for _, p := range []Position{
+		{MMSI: 1, Lat: 30.5, Lon: 32.2, At: at(5)},
+		{MMSI: 2, Lat: 30.5, Lon: 32.5, At: at(10)},
+		{MMSI: 1, Lat: 30.5, Lon: 32.8, At: at(15)},
+		{MMSI: 1, Lat: 30.5, Lon: 33.5, At: at(20)},
+	} {
+		if out, err := tr.Observe(p); err != nil || len(out) != 0 {
+			t.Fatalf("window closed early: %+v %v", out, err)
+		}
+	}
+	// A position from before the open window is ignored.
+	if _, err := tr.Observe(Position{MMSI: 3, Lat: 30.5, Lon: 32.5, At: base.Add(-time.Minute)}); !errors.Is(err, ErrLatePosition) {
+		t.Fatalf("err = %v, want ErrLatePosition", err)
+	}
+
+	signals := byMetric(tr.Advance(at(60)))
+	if len(signals) != 2 {
+		t.Fatalf("got %d signals, want 2", len(signals))
+	}
+	shortfall := signals[MetricTransitShortfall]
+	if shortfall.MetricValue != 50 || shortfall.Metadata["transits"] != "1" || !shortfall.Timestamp.Equal(at(60)) {
+		t.Fatalf("shortfall signal %+v", shortfall)
+	}
+	// Vessel 2 has been inside for 50 minutes.
+	if dwell := signals[MetricDwellAnomalies]; dwell.MetricValue != 1 || dwell.Metadata["vessels"] != "1" {
+		t.Fatalf("dwell signal %+v", dwell)
+	}
+
+	status := tr.Status()
+	if len(status) != 1 || status[0].Transits != 1 || status[0].Vessels != 1 || status[0].Positions != 4 {
+		t.Fatalf("status %+v", status)
+	}
+
+	// Replaying the window yields the same signal IDs.
+	again := NewTracker([]Lane{strait}, Options{Window: time.Hour})
+	for _, p := range []Position{
+		{MMSI: 1, Lat: 30.5, Lon: 32.2, At: at(5)},
+		{MMSI: 1, Lat: 30.5, Lon: 33.5, At: at(20)},
+	} {
+		observe(t, again, p)
+	}
+	if got := byMetric(again.Advance(at(60)))[MetricTransitShortfall].ID; got != shortfall.ID {
+		t.Fatalf("replayed id %s, want %s", got, shortfall.ID)
+	}
+}
+
+func TestTrackerExpiresStaleVessels(t *testing.T) {
+	tr := NewTracker([]Lane{strait}, Options{Window: time.Hour, StaleAfter: 2 * time.Hour})
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	observe(t, tr, Position{MMSI: 2, Lat: 30.5, Lon: 32.5, At: base.Add(10 * time.Minute)})
+	// Windows without positions are not reported.
+	for _, s := range tr.Advance(base.Add(3*time.Hour + 5*time.Minute)) {
+		if !s.Timestamp.Equal(base.Add(time.Hour)) {
+			t.Fatalf("signal for the empty window ending %s", s.Timestamp)
+		}
+	}
+
+	// Vessel 2 was last seen 2h50m before the 13:00 close and was dropped,
+	// so leaving the lane now is not a transit.
+	observe(t, tr, Position{MMSI: 2, Lat: 29, Lon: 32.5, At: base.Add(3*time.Hour + 10*time.Minute)})
+	observe(t, tr, Position{MMSI: 4, Lat: 30.5, Lon: 32.5, At: base.Add(3*time.Hour + 20*time.Minute)})
+	tr.Advance(base.Add(4 * time.Hour))
+
+	status := tr.Status()[0]
+	if status.Transits != 0 || status.Vessels != 1 || status.Positions != 1 {
+		t.Fatalf("status %+v", status)
+	}
+}
+
+func TestTrackerAllowedLateness(t *testing.T) {
+	tr := NewTracker([]Lane{strait}, Options{Window: time.Hour, AllowedLateness: 15 * time.Minute})
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+
+	observe(t, tr, Position{MMSI: 1, Lat: 30.5, Lon: 32.2, At: at(5)})
+	// A live receiver is ahead of the lagging one; the 10:00 window stays
+	// open and only counts its own positions.
+	observe(t, tr, Position{MMSI: 2, Lat: 30.5, Lon: 32.5, At: at(70)})
+	if out := tr.Advance(at(70)); len(out) != 0 {
+		t.Fatalf("window closed within the allowed lateness: %+v", out)
+	}
+	// Vessel 1 left the lane at 10:50, relayed twenty minutes late.
+	observe(t, tr, Position{MMSI: 1, Lat: 30.5, Lon: 33.5, At: at(50)})
+
+	signals := byMetric(tr.Advance(at(75)))
+	if s := signals[MetricTransitShortfall]; s.Metadata["transits"] != "1" || !s.Timestamp.Equal(at(60)) {
+		t.Fatalf("shortfall signal %+v, want the late transit in the 10:00 window", s)
+	}
+	if status := tr.Status()[0]; status.Positions != 2 {
+		t.Fatalf("status %+v, want the 11:10 position left for the next window", status)
+	}
+
+	if _, err := tr.Observe(Position{MMSI: 3, Lat: 30.5, Lon: 32.5, At: at(55)}); !errors.Is(err, ErrLatePosition) {
+		t.Fatalf("err = %v, want ErrLatePosition once the window closed", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package alerting decides which risk events open or resolve alerts. It is
+// shared by the alert-service and the query-api scenario simulator so that
+// both apply the same rules.
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/config"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+type Decision string
+
+const (
+	DecisionNone    Decision = "none"
+	DecisionOpen    Decision = "open"
+	DecisionResolve Decision = "resolve"
+)
+
+type Policy struct {
+	Threshold        float64
+	Cooldown         time.Duration
+	AutoResolveBelow float64
+	EscalateVelocity float64
+	// RequireLowerBound opens alerts only when the lower bound of the score
+	// interval reaches Threshold. Events without an interval, including
+	// windows too small to estimate one, are not confident enough to open.
+	RequireLowerBound bool
+}
+
+var ErrNoIntervals = errors.New("lower bound alerts need a scorer that reports intervals")
+
+func PolicyFromConfig(cfg config.Config) Policy {
+	return Policy{
+		Threshold:         cfg.AlertThreshold,
+		Cooldown:          cfg.AlertCooldown,
+		AutoResolveBelow:  cfg.AlertAutoResolveBelow,
+		EscalateVelocity:  cfg.AlertEscalateVelocity,
+		RequireLowerBound: cfg.AlertRequireLowerBound,
+	}
+}
+
+// Decide classifies a risk event. A key falling below AutoResolveBelow
+// resolves its open alerts unless its score is still rising; a score at or
+// above Threshold opens one, subject to the caller's cooldown check. Only the
+// event that crosses below AutoResolveBelow resolves: heartbeats keep
+// rescoring quiet keys, and the events that stay below have nothing left to
+// resolve.
+func (p Policy) Decide(event contracts.RiskEvent) Decision {
+	if event.RiskScore < p.AutoResolveBelow && event.Trend != contracts.TrendRising && p.crossedDown(event) {
+		return DecisionResolve
+	}
+	score := event.RiskScore
+	if p.RequireLowerBound {
+		if event.Uncertainty == nil {
+			return DecisionNone
+		}
+		score = event.Uncertainty.Lower
+	}
+	if score < p.Threshold {
+		return DecisionNone
+	}
+	return DecisionOpen
+}
+
+// Check refuses RequireLowerBound with a scorer that never reports an
+// interval, under which no alert would ever open.
+func (p Policy) Check(scorer risk.Scorer) error {
+	if !p.RequireLowerBound {
+		return nil
+	}
+	if _, ok := scorer.(risk.IntervalScorer); !ok {
+		return fmt.Errorf("%w: %s has none", ErrNoIntervals, scorer.Name())
+	}
+	return nil
+}
+
+// crossedDown reports whether the previous score of the key was at or above
+// AutoResolveBelow. A key without a previous score may have lost it in a
+// restart, so it counts as crossing.
+func (p Policy) crossedDown(event contracts.RiskEvent) bool {
+	return event.PreviousScore == nil || *event.PreviousScore >= p.AutoResolveBelow
+}
+
+// NewAlert builds the open alert for a risk event.
+func (p Policy) NewAlert(event contracts.RiskEvent) contracts.AlertRecord {
+	return contracts.AlertRecord{
+		ID:          uuid.NewString(),
+		RiskEventID: event.ID,
+		Country:     event.Country,
+		Region:      event.Region,
+		Commodity:   event.Commodity,
+		Title:       fmt.Sprintf("High disruption risk for %s", event.Commodity),
+		Description: Describe(event),
+		RiskScore:   event.RiskScore,
+		Severity:    p.Severity(event),
+		Status:      "open",
+	}
+}
+
+func Describe(event contracts.RiskEvent) string {
+	description := fmt.Sprintf("%s/%s scored %.2f (%s, %+.2f/h). %s", event.Country, event.Region, event.RiskScore, event.Trend, event.VelocityPerHour, event.RecommendedAction)
+	if iv := event.Uncertainty; iv != nil {
+		description += fmt.Sprintf(" %.0f%% interval %.2f-%.2f.", iv.Level*100, iv.Lower, iv.Upper)
+	}
+	if len(event.Actions) == 0 {
+		return description
+	}
+
+	top := event.Actions[0]
+	description += fmt.Sprintf(" [%s] owner=%s", top.Code, top.OwnerRole)
+	if !top.Deadline.IsZero() {
+		description += " due " + top.Deadline.UTC().Format(time.RFC3339)
+	}
+	return description
+}
+
+// Severity escalates one level when the score is rising at least as fast as
+// EscalateVelocity points per hour.
+func (p Policy) Severity(event contracts.RiskEvent) string {
+	severity := SeverityFromScore(event.RiskScore)
+	if p.EscalateVelocity <= 0 || event.Trend != contracts.TrendRising || event.VelocityPerHour < p.EscalateVelocity {
+		return severity
+	}
+
+	switch severity {
+	case "low":
+		return "medium"
+	case "medium":
+		return "high"
+	default:
+		return "critical"
+	}
+}
+
+func SeverityFromScore(score float64) string {
+	switch {
+	case score >= 90:
+		return "critical"
+	case score >= 75:
+		return "high"
+	case score >= 60:
+		return "medium"
+	default:
+		return "low"
+	}
+}
@@ -0,0 +1,67 @@
+package alerting
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+func TestDecide(t *testing.T) {
+	interval := func(lower float64) *contracts.ScoreInterval {
+		return &contracts.ScoreInterval{Lower: lower, Upper: 100, Level: 0.95}
+	}
+	previous := func(score float64) *float64 { return &score }
+	tests := []struct {
+		name        string
+		lowerBound  bool
+		previous    *float64
+		score       float64
+		trend       string
+		uncertainty *contracts.ScoreInterval
+		want        Decision
+	}{
+		{"above threshold", false, nil, 80, contracts.TrendStable, nil, DecisionOpen},
+		{"below threshold", false, nil, 60, contracts.TrendStable, nil, DecisionNone},
+		{"crosses below auto-resolve", false, previous(50), 10, contracts.TrendFalling, nil, DecisionResolve},
+		{"first score below auto-resolve", false, nil, 10, contracts.TrendNew, nil, DecisionResolve},
+		{"stays below auto-resolve", false, previous(15), 10, contracts.TrendFalling, nil, DecisionNone},
+		{"rising below auto-resolve", false, previous(5), 10, contracts.TrendRising, nil, DecisionNone},
+		{"interval ignored by default", false, nil, 80, contracts.TrendStable, interval(40), DecisionOpen},
+		{"lower bound below threshold", true, nil, 80, contracts.TrendStable, interval(40), DecisionNone},
+		{"lower bound at threshold", true, nil, 80, contracts.TrendStable, interval(70), DecisionOpen},
+		{"unknown interval is not confident", true, nil, 80, contracts.TrendStable, nil, DecisionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Policy{Threshold: 70, AutoResolveBelow: 20, RequireLowerBound: tt.lowerBound}
+			event := contracts.RiskEvent{RiskScore: tt.score, PreviousScore: tt.previous, Trend: tt.trend, Uncertainty: tt.uncertainty}
+			if got := p.Decide(event); got != tt.want {
+				t.Fatalf("Decide = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		scorer     string
+		lowerBound bool
+		wantErr    bool
+	}{
+		{"weighted_average", false, false},
+		{"weighted_average", true, true},
+		{"confidence_weighted", true, false},
+	}
+	for _, tt := range tests {
+		scorer, err := risk.LookupScorer(tt.scorer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Policy{RequireLowerBound: tt.lowerBound}.Check(scorer)
+		if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrNoIntervals)) {
+			t.Fatalf("Check(%s, lower bound %t) = %v, want error %t", tt.scorer, tt.lowerBound, err, tt.wantErr)
+		}
+	}
+}
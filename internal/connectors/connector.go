@@ -0,0 +1,183 @@
+// Package connectors polls external feeds on a schedule and turns their
+// records into signals. Each connector keeps a cursor so that a restart
+// resumes where the last completed poll stopped.
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const (
+	TypeCSVDir   = "csv_dir"
+	TypeRSS      = "rss"
+	TypeJSONHTTP = "json_http"
+)
+
+// DefaultInterval is used for connectors without interval_seconds.
+const DefaultInterval = 5 * time.Minute
+
+// Batch is one step of a poll. Cursor is the position after Signals; More
+// asks the runner to poll again immediately, e.g. for the next file.
+type Batch struct {
+	Signals []contracts.SignalEvent
+	Cursor  string
+	More    bool
+}
+
+// Connector reads the records after cursor from one source.
+type Connector interface {
+	Name() string
+	Poll(ctx context.Context, cursor string) (Batch, error)
+}
+
+// Spec configures one connector. Which fields apply depends on Type.
+type Spec struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	IntervalSeconds int    `json:"interval_seconds"`
+
+	// Dir and Pattern select the files of a csv_dir connector.
+	Dir     string `json:"dir"`
+	Pattern string `json:"pattern"`
+
+	// URL is the feed of an rss connector or the endpoint of a json_http
+	// connector.
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+
+	// ItemsPath is the dot path to the array of records in a json_http
+	// response; empty means the response is the array. CursorField is the
+	// record field that orders records and is required; CursorParam is the
+	// query parameter the saved cursor is sent in.
+	ItemsPath   string `json:"items_path"`
+	CursorField string `json:"cursor_field"`
+	CursorParam string `json:"cursor_param"`
+
+	// Mapping maps signal fields to CSV columns or JSON paths. Fields
+	// without a mapping use the column or path of the same name.
+	Mapping map[string]string `json:"mapping"`
+	// Defaults fills signal fields the record does not set.
+	Defaults contracts.SignalEvent `json:"defaults"`
+	// Rules classify rss items by keyword.
+	Rules []Rule `json:"rules"`
+}
+
+func (s Spec) Interval() time.Duration {
+	if s.IntervalSeconds <= 0 {
+		return DefaultInterval
+	}
+	return time.Duration(s.IntervalSeconds) * time.Second
+}
+
+type File struct {
+	Connectors []Spec `json:"connectors"`
+}
+
+// Load reads connector specs from a JSON file. An empty path configures no
+// connectors.
+func Load(path string) ([]Spec, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read connectors: %w", err)
+	}
+	var f File
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse connectors: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, spec := range f.Connectors {
+		if strings.TrimSpace(spec.Name) == "" {
+			return nil, fmt.Errorf("connector %d: name is required", i)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("connector %q is listed twice", spec.Name)
+		}
+		seen[spec.Name] = true
+		if err := spec.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return f.Connectors, nil
+}
+
+// validate checks the fields the spec's type needs.
+func (s Spec) validate() error {
+	switch s.Type {
+	case TypeCSVDir:
+		if s.Dir == "" {
+			return fmt.Errorf("connector %q: dir is required", s.Name)
+		}
+	case TypeRSS:
+		if s.URL == "" {
+			return fmt.Errorf("connector %q: url is required", s.Name)
+		}
+	case TypeJSONHTTP:
+		if s.URL == "" {
+			return fmt.Errorf("connector %q: url is required", s.Name)
+		}
+		// Without a cursor every poll reads the whole response again, and
+		// records are republished once they leave the ingest dedup cache.
+		if s.CursorField == "" {
+			return fmt.Errorf("connector %q: cursor_field is required", s.Name)
+		}
+	default:
+		return fmt.Errorf("connector %q: unknown type %q", s.Name, s.Type)
+	}
+	return nil
+}
+
+// New builds the connector a spec describes. client is used by the HTTP
+// based connectors; nil means http.DefaultClient.
+func New(spec Spec, client *http.Client) (Connector, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	switch spec.Type {
+	case TypeCSVDir:
+		return &csvDir{spec: spec}, nil
+	case TypeRSS:
+		return &rssFeed{spec: spec, client: client}, nil
+	default:
+		return &jsonHTTP{spec: spec, client: client}, nil
+	}
+}
+
+// ErrRejected marks a signal the sink refused, e.g. for failing
+// validation. The runner skips it instead of retrying the poll.
+var ErrRejected = errors.New("signal rejected")
+
+// fetch GETs url with the spec's headers and fails on non-2xx responses.
+func fetch(ctx context.Context, client *http.Client, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
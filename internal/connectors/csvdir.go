@@ -0,0 +1,112 @@
+package connectors
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// csvDir reads CSV files dropped into a directory, one file per batch in
+// name order. The cursor is the name of the last file read, so producers
+// should name files so that they sort by arrival, e.g. with a timestamp
+// prefix. The first row of each file is the header.
+type csvDir struct {
+	spec Spec
+}
+
+func (c *csvDir) Name() string { return c.spec.Name }
+
+func (c *csvDir) Poll(ctx context.Context, cursor string) (Batch, error) {
+	pattern := c.spec.Pattern
+	if pattern == "" {
+		pattern = "*.csv"
+	}
+	paths, err := filepath.Glob(filepath.Join(c.spec.Dir, pattern))
+	if err != nil {
+		return Batch{}, fmt.Errorf("list %s: %w", c.spec.Dir, err)
+	}
+
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if name := filepath.Base(path); name > cursor {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return Batch{Cursor: cursor}, nil
+	}
+	sort.Strings(names)
+
+	name := names[0]
+	signals, err := c.readFile(ctx, name)
+	if err != nil {
+		return Batch{}, err
+	}
+	return Batch{Signals: signals, Cursor: name, More: len(names) > 1}, nil
+}
+
+func (c *csvDir) readFile(ctx context.Context, name string) ([]contracts.SignalEvent, error) {
+	f, err := os.Open(filepath.Join(c.spec.Dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		// Re-reading a malformed file would fail the same way, so it is
+		// skipped rather than blocking the files after it.
+		log.Printf("connector %s: %s skipped: %v", c.spec.Name, name, err)
+		return nil, nil
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	var signals []contracts.SignalEvent
+	for row := 2; ; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Printf("connector %s: %s truncated at row %d: %v", c.spec.Name, name, row, err)
+			break
+		}
+
+		get := func(column string) (string, bool) {
+			i, ok := columns[strings.ToLower(column)]
+			if !ok || i >= len(record) {
+				return "", false
+			}
+			return record[i], true
+		}
+		signal, err := mapRecord(c.spec, fmt.Sprintf("%s:%d", name, row), get)
+		if err != nil {
+			log.Printf("connector %s: %s row %d skipped: %v", c.spec.Name, name, row, err)
+			continue
+		}
+		signals = append(signals, signal)
+	}
+	return signals, nil
+}
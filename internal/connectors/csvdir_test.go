@@ -0,0 +1,57 @@
+package connectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func writeFile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCSVDirPoll(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "001.csv", "Country,Commodity,Metric_Value,Timestamp\nUS,wheat,12,2026-03-02T10:00:00Z\nDE,rice,not-a-number,2026-03-02T10:00:00Z\n")
+	writeFile(t, dir, "002.csv", "country,commodity,metric_value\nBR,maize,3\n")
+	writeFile(t, dir, "notes.txt", "ignored")
+
+	c, err := New(Spec{Name: "drops", Type: TypeCSVDir, Dir: dir, Defaults: contracts.SignalEvent{Source: contracts.SourcePortCongestion, MetricName: "queue_index"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := c.Poll(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The row with a bad metric_value is skipped.
+	if batch.Cursor != "001.csv" || !batch.More || len(batch.Signals) != 1 {
+		t.Fatalf("first batch = %+v", batch)
+	}
+	if s := batch.Signals[0]; s.Country != "US" || s.Commodity != "wheat" || s.MetricValue != 12 || s.Source != contracts.SourcePortCongestion {
+		t.Fatalf("unexpected signal %+v", s)
+	}
+
+	batch, err = c.Poll(context.Background(), batch.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.Cursor != "002.csv" || batch.More || len(batch.Signals) != 1 {
+		t.Fatalf("second batch = %+v", batch)
+	}
+
+	batch, err = c.Poll(context.Background(), batch.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.Cursor != "002.csv" || len(batch.Signals) != 0 {
+		t.Fatalf("empty batch = %+v", batch)
+	}
+}
@@ -0,0 +1,148 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func priceServer(t *testing.T, seen *[]string) *httptest.Server {
+	t.Helper()
+	records := []map[string]any{
+		{"observed_at": "2026-03-02T10:00:00Z", "iso2": "DE", "product": "wheat", "change_pct": 4.5},
+		{"observed_at": "2026-03-02T11:00:00Z", "iso2": "FR", "product": "maize", "change_pct": "7"},
+		{"iso2": "IT", "product": "rice", "change_pct": 1},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = append(*seen, r.URL.Query().Get("since"))
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"items": records}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func priceSpec(url string) Spec {
+	return Spec{
+		Name:        "prices",
+		Type:        TypeJSONHTTP,
+		URL:         url,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ItemsPath:   "data.items",
+		CursorField: "observed_at",
+		CursorParam: "since",
+		Mapping:     map[string]string{"timestamp": "observed_at", "country": "iso2", "commodity": "product", "metric_value": "change_pct"},
+		Defaults:    contracts.SignalEvent{Source: contracts.SourcePriceSpike, MetricName: "price_change_percent"},
+	}
+}
+
+func TestJSONHTTPPoll(t *testing.T) {
+	var seen []string
+	server := priceServer(t, &seen)
+	c, err := New(priceSpec(server.URL), server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := c.Poll(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The record without observed_at is skipped.
+	if len(batch.Signals) != 2 {
+		t.Fatalf("signals = %d, want 2", len(batch.Signals))
+	}
+	if s := batch.Signals[1]; s.Country != "FR" || s.Commodity != "maize" || s.MetricValue != 7 || s.Source != contracts.SourcePriceSpike {
+		t.Fatalf("unexpected signal %+v", s)
+	}
+	if batch.Cursor != "2026-03-02T11:00:00Z" {
+		t.Fatalf("cursor = %q", batch.Cursor)
+	}
+
+	again, err := c.Poll(context.Background(), batch.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The record at the cursor is read again under the same ID.
+	if len(again.Signals) != 1 || again.Signals[0].ID != batch.Signals[1].ID || again.Cursor != batch.Cursor {
+		t.Fatalf("resumed batch = %+v", again)
+	}
+	if len(seen) != 2 || seen[0] != "" || seen[1] != batch.Cursor {
+		t.Fatalf("cursor params = %q", seen)
+	}
+}
+
+// A record that shows up after a poll with the same cursor value as the
+// last record read is still picked up by the next poll.
+func TestJSONHTTPCursorTie(t *testing.T) {
+	pages := [][]map[string]any{
+		{
+			{"observed_at": "2026-03-02T10:00:00Z", "iso2": "DE", "product": "wheat", "change_pct": 4.5},
+			{"observed_at": "2026-03-02T11:00:00Z", "iso2": "FR", "product": "maize", "change_pct": 7},
+		},
+		{
+			{"observed_at": "2026-03-02T11:00:00Z", "iso2": "FR", "product": "maize", "change_pct": 7},
+			{"observed_at": "2026-03-02T11:00:00Z", "iso2": "ES", "product": "maize", "change_pct": 3},
+		},
+	}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"items": pages[min(polls, len(pages)-1)]}})
+		polls++
+	}))
+	t.Cleanup(server.Close)
+	spec := priceSpec(server.URL)
+	spec.Headers = nil
+	c, err := New(spec, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := c.Poll(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Poll(context.Background(), first.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Signals) != 2 || second.Cursor != first.Cursor {
+		t.Fatalf("second poll = %+v", second)
+	}
+	if second.Signals[0].ID != first.Signals[1].ID {
+		t.Fatalf("re-read record has ID %s, want %s", second.Signals[0].ID, first.Signals[1].ID)
+	}
+	if tied := second.Signals[1]; tied.Country != "ES" || tied.ID == first.Signals[1].ID {
+		t.Fatalf("tied record = %+v", tied)
+	}
+}
+
+func TestJSONHTTPErrorStatus(t *testing.T) {
+	var seen []string
+	server := priceServer(t, &seen)
+	spec := priceSpec(server.URL)
+	spec.Headers = nil
+	c, err := New(spec, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Poll(context.Background(), ""); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("err = %v, want a 401 error", err)
+	}
+}
+
+func TestJSONHTTPRequiresCursorField(t *testing.T) {
+	spec := priceSpec("http://example.invalid")
+	spec.CursorField = ""
+	if _, err := New(spec, nil); err == nil {
+		t.Fatal("spec without cursor_field was accepted")
+	}
+}
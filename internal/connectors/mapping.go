@@ -0,0 +1,113 @@
+package connectors
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// idNamespace derives signal IDs from connector records, so a record read
+// twice gets the same ID and is dropped by the ingest dedup cache and the
+// risk-engine window.
+var idNamespace = uuid.MustParse("6f1c7a52-3f0e-4d7a-9a55-2f2b8c1e0d41")
+
+func recordID(connector, key string) string {
+	return uuid.NewSHA1(idNamespace, []byte(connector+"|"+key)).String()
+}
+
+var signalFields = []string{"id", "timestamp", "source", "country", "region", "commodity", "metric_name", "metric_value", "severity", "confidence"}
+
+// mapRecord builds a signal from a record. get looks up a CSV column or
+// JSON path; key identifies the record when it has no id of its own.
+func mapRecord(spec Spec, key string, get func(column string) (string, bool)) (contracts.SignalEvent, error) {
+	s := spec.Defaults
+	s.ID = ""
+
+	for _, field := range signalFields {
+		column := field
+		if mapped, ok := spec.Mapping[field]; ok {
+			column = mapped
+		}
+		raw, ok := get(column)
+		raw = strings.TrimSpace(raw)
+		if !ok || raw == "" {
+			continue
+		}
+
+		switch field {
+		case "id":
+			key = "id:" + raw
+		case "timestamp":
+			ts, err := parseTime(raw)
+			if err != nil {
+				return s, fmt.Errorf("timestamp: %w", err)
+			}
+			s.Timestamp = ts
+		case "source":
+			s.Source = contracts.SignalSource(raw)
+		case "country":
+			s.Country = raw
+		case "region":
+			s.Region = raw
+		case "commodity":
+			s.Commodity = raw
+		case "metric_name":
+			s.MetricName = raw
+		case "metric_value":
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return s, fmt.Errorf("metric_value: %w", err)
+			}
+			s.MetricValue = v
+		case "severity":
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return s, fmt.Errorf("severity: %w", err)
+			}
+			s.Severity = int(math.Round(v))
+		case "confidence":
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return s, fmt.Errorf("confidence: %w", err)
+			}
+			s.Confidence = v
+		}
+	}
+
+	s.ID = recordID(spec.Name, key)
+	return s, nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+}
+
+// parseTime accepts the common feed formats and Unix seconds.
+func parseTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range timeLayouts {
+		if ts, err := time.Parse(layout, raw); err == nil {
+			return ts.UTC(), nil
+		}
+	}
+	if secs, err := strconv.ParseFloat(raw, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*1e9)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", raw)
+}
@@ -0,0 +1,182 @@
+package connectors
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// Rule turns a news item that mentions any of Keywords into a signal for
+// the given key. Empty fields fall back to the connector defaults.
+type Rule struct {
+	Keywords  []string `json:"keywords"`
+	Country   string   `json:"country"`
+	Region    string   `json:"region"`
+	Commodity string   `json:"commodity"`
+	Severity  int      `json:"severity"`
+}
+
+// rssFeed polls an RSS 2.0 or Atom feed. Items are matched against the
+// rules in order; the first match decides the signal and items matching
+// no rule are ignored. Without rules every item becomes a signal from the
+// defaults. The cursor is the newest item time already read.
+type rssFeed struct {
+	spec   Spec
+	client *http.Client
+}
+
+type feedDoc struct {
+	Items   []rssItem   `xml:"channel>item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+type atomEntry struct {
+	Title     string `xml:"title"`
+	ID        string `xml:"id"`
+	Updated   string `xml:"updated"`
+	Published string `xml:"published"`
+	Summary   string `xml:"summary"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+}
+
+type feedItem struct {
+	key  string
+	text string
+	at   time.Time
+}
+
+func (f *rssFeed) Name() string { return f.spec.Name }
+
+func (f *rssFeed) Poll(ctx context.Context, cursor string) (Batch, error) {
+	var since time.Time
+	if cursor != "" {
+		ts, err := time.Parse(time.RFC3339Nano, cursor)
+		if err != nil {
+			return Batch{}, fmt.Errorf("cursor: %w", err)
+		}
+		since = ts
+	}
+
+	resp, err := fetch(ctx, f.client, f.spec.URL, f.spec.Headers)
+	if err != nil {
+		return Batch{}, err
+	}
+	defer resp.Body.Close()
+
+	var doc feedDoc
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return Batch{}, fmt.Errorf("parse feed: %w", err)
+	}
+
+	items := f.items(doc)
+	sort.Slice(items, func(i, j int) bool { return items[i].at.Before(items[j].at) })
+
+	batch := Batch{Cursor: cursor}
+	for _, item := range items {
+		if !item.at.After(since) {
+			continue
+		}
+		batch.Cursor = item.at.Format(time.RFC3339Nano)
+		if signal, ok := f.classify(item); ok {
+			batch.Signals = append(batch.Signals, signal)
+		}
+	}
+	return batch, nil
+}
+
+func (f *rssFeed) items(doc feedDoc) []feedItem {
+	out := make([]feedItem, 0, len(doc.Items)+len(doc.Entries))
+	add := func(key, title, body, date string) {
+		at, err := parseTime(date)
+		if err != nil {
+			log.Printf("connector %s: item %q skipped: %v", f.spec.Name, title, err)
+			return
+		}
+		if key == "" {
+			key = title
+		}
+		out = append(out, feedItem{key: key, text: strings.ToLower(title + " " + body), at: at})
+	}
+
+	for _, item := range doc.Items {
+		key := item.GUID
+		if key == "" {
+			key = item.Link
+		}
+		add(key, item.Title, item.Description, item.PubDate)
+	}
+	for _, entry := range doc.Entries {
+		key := entry.ID
+		if key == "" && len(entry.Links) > 0 {
+			key = entry.Links[0].Href
+		}
+		date := entry.Updated
+		if date == "" {
+			date = entry.Published
+		}
+		add(key, entry.Title, entry.Summary, date)
+	}
+	return out
+}
+
+func (f *rssFeed) classify(item feedItem) (contracts.SignalEvent, bool) {
+	s := f.spec.Defaults
+	s.ID = recordID(f.spec.Name, item.key)
+	s.Timestamp = item.at
+	if s.Source == "" {
+		s.Source = contracts.SourceNews
+	}
+	if s.MetricName == "" {
+		s.MetricName = "keyword_hits"
+	}
+	if len(f.spec.Rules) == 0 {
+		return s, true
+	}
+
+	for _, rule := range f.spec.Rules {
+		hits := 0
+		for _, keyword := range rule.Keywords {
+			if keyword != "" && strings.Contains(item.text, strings.ToLower(keyword)) {
+				hits++
+			}
+		}
+		if hits == 0 {
+			continue
+		}
+
+		if rule.Country != "" {
+			s.Country = rule.Country
+		}
+		if rule.Region != "" {
+			s.Region = rule.Region
+		}
+		if rule.Commodity != "" {
+			s.Commodity = rule.Commodity
+		}
+		if rule.Severity != 0 {
+			s.Severity = rule.Severity
+		}
+		if s.MetricValue == 0 {
+			s.MetricValue = float64(hits)
+		}
+		return s, true
+	}
+	return contracts.SignalEvent{}, false
+}
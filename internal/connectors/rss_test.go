@@ -0,0 +1,100 @@
+package connectors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const rssFixture = `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+  <item><title>Dock strike halts port</title><guid>a</guid><pubDate>Mon, 02 Mar 2026 10:00:00 +0000</pubDate><description>Insulin shipments stuck.</description></item>
+  <item><title>Weather is fine</title><guid>b</guid><pubDate>Mon, 02 Mar 2026 11:00:00 +0000</pubDate></item>
+  <item><title>Port strike spreads</title><guid>c</guid><pubDate>Mon, 02 Mar 2026 12:00:00 +0000</pubDate></item>
+</channel></rss>`
+
+const atomFixture = `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry><title>Port strike</title><id>urn:1</id><updated>2026-03-02T09:00:00Z</updated><summary>dock strike</summary></entry>
+  <entry><title>Quiet day</title><id>urn:2</id><published>2026-03-02T10:00:00Z</published></entry>
+</feed>`
+
+func serve(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func strikeSpec(url string) Spec {
+	return Spec{
+		Name: "news",
+		Type: TypeRSS,
+		URL:  url,
+		Defaults: contracts.SignalEvent{
+			Country:   "IN",
+			Commodity: "insulin",
+		},
+		Rules: []Rule{{Keywords: []string{"port strike", "dock strike"}, Region: "coastal", Severity: 7}},
+	}
+}
+
+func TestRSSPoll(t *testing.T) {
+	server := serve(t, rssFixture)
+	c, err := New(strikeSpec(server.URL), server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := c.Poll(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Signals) != 2 {
+		t.Fatalf("signals = %d, want 2", len(batch.Signals))
+	}
+	first := batch.Signals[0]
+	if first.Region != "coastal" || first.Severity != 7 || first.Source != contracts.SourceNews || first.MetricValue != 1 {
+		t.Fatalf("unexpected signal %+v", first)
+	}
+	if want := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC).Format(time.RFC3339Nano); batch.Cursor != want {
+		t.Fatalf("cursor = %q, want %q", batch.Cursor, want)
+	}
+
+	// Items at or before the cursor are not read again.
+	again, err := c.Poll(context.Background(), time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC).Format(time.RFC3339Nano))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Signals) != 1 || again.Signals[0].ID != batch.Signals[1].ID {
+		t.Fatalf("resumed signals = %+v", again.Signals)
+	}
+}
+
+func TestAtomPoll(t *testing.T) {
+	server := serve(t, atomFixture)
+	c, err := New(strikeSpec(server.URL), server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := c.Poll(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Signals) != 1 {
+		t.Fatalf("signals = %d, want 1", len(batch.Signals))
+	}
+	if got := batch.Signals[0].MetricValue; got != 2 {
+		t.Fatalf("keyword hits = %v, want 2", got)
+	}
+	if want := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC).Format(time.RFC3339Nano); batch.Cursor != want {
+		t.Fatalf("cursor = %q, want %q", batch.Cursor, want)
+	}
+}
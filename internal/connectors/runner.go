@@ -0,0 +1,209 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// CursorStore persists connector cursors. storage.Repository implements it
+// on Postgres; MemoryCursors keeps them in memory for local runs.
+type CursorStore interface {
+	LoadCursor(ctx context.Context, name string) (string, error)
+	SaveCursor(ctx context.Context, name, cursor string) error
+}
+
+// Sink publishes one signal. It returns an error wrapping ErrRejected for
+// signals that should be skipped and any other error to abort the poll.
+type Sink func(ctx context.Context, signal contracts.SignalEvent) error
+
+// maxBatchesPerRun bounds how many batches one run reads before yielding
+// to the next tick.
+const maxBatchesPerRun = 100
+
+type Status struct {
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Interval    string    `json:"interval"`
+	Cursor      string    `json:"cursor"`
+	LastRun     time.Time `json:"last_run,omitzero"`
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	Published   int64     `json:"published"`
+	Rejected    int64     `json:"rejected"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+type job struct {
+	connector Connector
+	interval  time.Duration
+}
+
+// Runner polls each connector on its own schedule, hands the signals to
+// the sink and saves the cursor only after every signal of a batch was
+// published, so an interrupted batch is read again rather than lost.
+type Runner struct {
+	cursors CursorStore
+	sink    Sink
+
+	mu     sync.Mutex
+	jobs   []job
+	status map[string]*Status
+}
+
+func NewRunner(cursors CursorStore, sink Sink) *Runner {
+	return &Runner{
+		cursors: cursors,
+		sink:    sink,
+		status:  make(map[string]*Status),
+	}
+}
+
+func (r *Runner) Add(spec Spec, c Connector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job{connector: c, interval: spec.Interval()})
+	r.status[c.Name()] = &Status{Name: c.Name(), Type: spec.Type, Interval: spec.Interval().String()}
+}
+
+// Run polls every connector immediately and then on its interval until ctx
+// is done.
+func (r *Runner) Run(ctx context.Context) {
+	r.mu.Lock()
+	jobs := append([]job(nil), r.jobs...)
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+			for {
+				if err := r.RunOnce(ctx, j.connector); err != nil && ctx.Err() == nil {
+					log.Printf("connector %s error: %v", j.connector.Name(), err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(j)
+	}
+	wg.Wait()
+}
+
+// RunOnce reads everything the connector has after its saved cursor.
+func (r *Runner) RunOnce(ctx context.Context, c Connector) (err error) {
+	name := c.Name()
+	var published, rejected int64
+	defer func() {
+		r.record(name, published, rejected, err)
+	}()
+
+	cursor, err := r.cursors.LoadCursor(ctx, name)
+	if err != nil {
+		return err
+	}
+	r.setCursor(name, cursor)
+
+	for range maxBatchesPerRun {
+		batch, err := c.Poll(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		for _, signal := range batch.Signals {
+			if err := r.sink(ctx, signal); err != nil {
+				if errors.Is(err, ErrRejected) {
+					rejected++
+					log.Printf("connector %s: signal %s rejected: %v", name, signal.ID, err)
+					continue
+				}
+				return err
+			}
+			published++
+		}
+
+		if batch.Cursor != cursor {
+			if err := r.cursors.SaveCursor(ctx, name, batch.Cursor); err != nil {
+				return err
+			}
+			cursor = batch.Cursor
+			r.setCursor(name, cursor)
+		}
+		if !batch.More {
+			break
+		}
+	}
+	return nil
+}
+
+func (r *Runner) record(name string, published, rejected int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.status[name]
+	if !ok {
+		s = &Status{Name: name}
+		r.status[name] = s
+	}
+	now := time.Now().UTC()
+	s.LastRun = now
+	s.Published += published
+	s.Rejected += rejected
+	if err != nil {
+		s.LastError = err.Error()
+		return
+	}
+	s.LastSuccess = now
+	s.LastError = ""
+}
+
+func (r *Runner) setCursor(name, cursor string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.status[name]; ok {
+		s.Cursor = cursor
+	}
+}
+
+// Status reports every connector, ordered by name.
+func (r *Runner) Status() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Status, 0, len(r.status))
+	for _, s := range r.status {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// MemoryCursors is a CursorStore that forgets everything on restart.
+type MemoryCursors struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+func (m *MemoryCursors) LoadCursor(_ context.Context, name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cursors[name], nil
+}
+
+func (m *MemoryCursors) SaveCursor(_ context.Context, name, cursor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cursors == nil {
+		m.cursors = make(map[string]string)
+	}
+	m.cursors[name] = cursor
+	return nil
+}
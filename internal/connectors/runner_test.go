@@ -0,0 +1,62 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestRunnerResumesFromCursor(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "001.csv", "country,commodity,metric_value\nUS,wheat,1\nUS,rice,2\n")
+	writeFile(t, dir, "002.csv", "country,commodity,metric_value\nDE,maize,3\n")
+
+	spec := Spec{Name: "drops", Type: TypeCSVDir, Dir: dir}
+	c, err := New(spec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cursors := &MemoryCursors{}
+	var published []contracts.SignalEvent
+	fail := true
+	sink := func(_ context.Context, s contracts.SignalEvent) error {
+		switch {
+		case s.Commodity == "rice":
+			return fmt.Errorf("%w: unknown commodity", ErrRejected)
+		case s.Commodity == "maize" && fail:
+			return errors.New("kafka down")
+		}
+		published = append(published, s)
+		return nil
+	}
+	runner := NewRunner(cursors, sink)
+	runner.Add(spec, c)
+
+	// The second file fails to publish, so its cursor is not saved.
+	if err := runner.RunOnce(context.Background(), c); err == nil {
+		t.Fatal("expected the publish error")
+	}
+	if cursor, _ := cursors.LoadCursor(context.Background(), "drops"); cursor != "001.csv" {
+		t.Fatalf("cursor = %q, want 001.csv", cursor)
+	}
+
+	fail = false
+	if err := runner.RunOnce(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.RunOnce(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 2 || published[0].Commodity != "wheat" || published[1].Commodity != "maize" {
+		t.Fatalf("published = %+v", published)
+	}
+
+	status := runner.Status()
+	if len(status) != 1 || status[0].Cursor != "002.csv" || status[0].Published != 2 || status[0].Rejected != 1 || status[0].LastError != "" {
+		t.Fatalf("status = %+v", status)
+	}
+}
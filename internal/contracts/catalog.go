@@ -0,0 +1,26 @@
+package contracts
+
+import "time"
+
+// CatalogCommodity is a commodity in the reference catalog. Name is the
+// canonical key used in signals; aliases resolve to it.
+type CatalogCommodity struct {
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	Category    string    `json:"category"`
+	HSCodes     []string  `json:"hs_codes"`
+	Aliases     []string  `json:"aliases"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+}
+
+// CatalogRegion is a region in the reference catalog. An empty Country
+// means the region exists in every country. Parent links it into the
+// region hierarchy, whose root is "global".
+type CatalogRegion struct {
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	Country     string    `json:"country,omitempty"`
+	Parent      string    `json:"parent,omitempty"`
+	Aliases     []string  `json:"aliases"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+}
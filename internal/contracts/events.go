@@ -1,6 +1,11 @@
 package contracts
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SignalSource string
 
@@ -26,25 +31,143 @@ type SignalEvent struct {
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
+const (
+	RiskTriggerSignal    = "signal"
+	RiskTriggerHeartbeat = "heartbeat"
+	RiskTriggerContagion = "contagion"
+)
+
+const (
+	ContributorSignal     = "signal"
+	ContributorPropagated = "propagated"
+)
+
+const (
+	TrendNew     = "new"
+	TrendRising  = "rising"
+	TrendStable  = "stable"
+	TrendFalling = "falling"
+)
+
 type RiskContributor struct {
+	Type            string       `json:"type"`
+	Origin          string       `json:"origin,omitempty"`
+	Source          SignalSource `json:"source"`
+	MetricName      string       `json:"metric_name"`
+	MetricValue     float64      `json:"metric_value"`
+	NormalizedValue float64      `json:"normalized_value"`
+	Score           float64      `json:"score"`
+	Weight          float64      `json:"weight"`
+	Flags           []string     `json:"flags,omitempty"`
+	Anomaly         bool         `json:"anomaly"`
+	ZScore          float64      `json:"z_score"`
+}
+
+// SignalBreakdown shows how one signal's score was put together: the three
+// additive components and the multipliers applied to their sum.
+type SignalBreakdown struct {
+	SignalID            string       `json:"signal_id"`
+	Timestamp           time.Time    `json:"timestamp"`
+	Source              SignalSource `json:"source"`
+	MetricName          string       `json:"metric_name"`
+	MetricValue         float64      `json:"metric_value"`
+	NormalizedValue     float64      `json:"normalized_value"`
+	Severity            int          `json:"severity"`
+	Confidence          float64      `json:"confidence"`
+	SeverityComponent   float64      `json:"severity_component"`
+	ConfidenceComponent float64      `json:"confidence_component"`
+	ValueComponent      float64      `json:"value_component"`
+	SourceWeight        float64      `json:"source_weight"`
+	AnomalyBoost        float64      `json:"anomaly_boost"`
+	Score               float64      `json:"score"`
+	DecayWeight         float64      `json:"decay_weight"`
+	// AggregationWeight is the weight of the signal in the mean for scorers
+	// that weight by more than decay.
+	AggregationWeight float64  `json:"aggregation_weight,omitempty"`
+	Flags             []string `json:"flags,omitempty"`
+}
+
+// InboundBreakdown is risk a key received from another key.
+type InboundBreakdown struct {
+	Origin string  `json:"origin"`
+	Kind   string  `json:"kind"`
+	Score  float64 `json:"score"`
+	Factor float64 `json:"factor"`
+	Hops   int     `json:"hops"`
+	Value  float64 `json:"value"`
+}
+
+// RiskBreakdown explains a risk score in full. OwnScore is what the scorer
+// produced from the key's window; the final score is the higher of it and
+// InboundScore.
+type RiskBreakdown struct {
+	Scorer       string             `json:"scorer"`
+	SignalCount  int                `json:"signal_count"`
+	OwnScore     float64            `json:"own_score"`
+	InboundScore float64            `json:"inbound_score"`
+	Signals      []SignalBreakdown  `json:"signals"`
+	Inbound      []InboundBreakdown `json:"inbound"`
+}
+
+// ScoreInterval is the uncertainty of a risk score at the given confidence
+// level.
+type ScoreInterval struct {
+	StdErr float64 `json:"std_err"`
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+	Level  float64 `json:"level"`
+}
+
+type RecommendedAction struct {
+	Code      string    `json:"code"`
+	Text      string    `json:"text"`
+	OwnerRole string    `json:"owner_role"`
+	Deadline  time.Time `json:"deadline"`
+	RuleID    string    `json:"rule_id"`
+}
+
+type RiskEvent struct {
+	ID                string              `json:"id"`
+	Timestamp         time.Time           `json:"timestamp"`
+	Country           string              `json:"country"`
+	Region            string              `json:"region"`
+	Commodity         string              `json:"commodity"`
+	RiskScore         float64             `json:"risk_score"`
+	Uncertainty       *ScoreInterval      `json:"uncertainty,omitempty"`
+	PreviousScore     *float64            `json:"previous_score"`
+	ScoreDelta        float64             `json:"score_delta"`
+	VelocityPerHour   float64             `json:"velocity_per_hour"`
+	Trend             string              `json:"trend"`
+	WindowMinutes     int                 `json:"window_minutes"`
+	Contributors      []RiskContributor   `json:"contributors"`
+	AnomalyCount      int                 `json:"anomaly_count"`
+	SignalCount       int                 `json:"signal_count"`
+	RecommendedAction string              `json:"recommended_action"`
+	Actions           []RecommendedAction `json:"actions"`
+	Trigger           string              `json:"trigger"`
+	PolicyVersion     string              `json:"policy_version"`
+	// Breakdown is stored with the event but not published.
+	Breakdown *RiskBreakdown `json:"-"`
+	// Anomaly is the triggering signal when it was flagged as an anomaly.
+	// Like Breakdown it is stored but not published.
+	Anomaly *SignalAnomaly `json:"-"`
+	// OwnScore is the score of the key's own signals before inbound risk.
+	// It is what the key passes on to its neighbours and is not published.
+	OwnScore float64 `json:"-"`
+}
+
+// SignalAnomaly is a signal that deviated from its key's baseline by more
+// than the anomaly threshold.
+type SignalAnomaly struct {
+	SignalID    string       `json:"signal_id"`
+	Timestamp   time.Time    `json:"timestamp"`
 	Source      SignalSource `json:"source"`
 	MetricName  string       `json:"metric_name"`
 	MetricValue float64      `json:"metric_value"`
+	ZScore      float64      `json:"z_score"`
 	Score       float64      `json:"score"`
 }
 
-type RiskEvent struct {
-	ID                string            `json:"id"`
-	Timestamp         time.Time         `json:"timestamp"`
-	Country           string            `json:"country"`
-	Region            string            `json:"region"`
-	Commodity         string            `json:"commodity"`
-	RiskScore         float64           `json:"risk_score"`
-	WindowMinutes     int               `json:"window_minutes"`
-	Contributors      []RiskContributor `json:"contributors"`
-	RecommendedAction string            `json:"recommended_action"`
-}
-
 type AlertRecord struct {
 	ID          string    `json:"id"`
 	RiskEventID string    `json:"risk_event_id"`
@@ -63,3 +186,38 @@ type AlertRecord struct {
 func (s SignalEvent) Key() string {
 	return s.Country + "|" + s.Region + "|" + s.Commodity
 }
+
+// Enrich fills in the defaults for fields a producer left unset and puts the
+// key fields in canonical case. Ingest applies it to every signal it accepts
+// and the scenario simulator to every hypothetical one, so both score the
+// same input alike.
+func (s *SignalEvent) Enrich(now time.Time) {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	if s.Timestamp.IsZero() {
+		s.Timestamp = now
+	}
+	s.Country = strings.ToUpper(strings.TrimSpace(s.Country))
+	// Regions are lower case like the contagion graph nodes, so that
+	// transfers address the same keys that signals create.
+	s.Region = strings.ToLower(strings.TrimSpace(s.Region))
+	if s.Region == "" {
+		s.Region = "global"
+	}
+	s.Commodity = strings.ToLower(strings.TrimSpace(s.Commodity))
+	// Zero means the producer did not set the field; out of range values
+	// are left for validation to reject or clamp.
+	if s.Severity == 0 {
+		s.Severity = 1
+	}
+	if s.Confidence == 0 {
+		s.Confidence = 0.6
+	}
+	if s.Source == "" {
+		s.Source = SourceNews
+	}
+	if s.MetricName == "" {
+		s.MetricName = "composite_signal"
+	}
+}
@@ -0,0 +1,42 @@
+package contracts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnrichKey(t *testing.T) {
+	tests := []struct {
+		name string
+		in   SignalEvent
+		want string
+	}{
+		{"canonical", SignalEvent{Country: "IN", Region: "coastal", Commodity: "rice"}, "IN|coastal|rice"},
+		{"mixed case", SignalEvent{Country: " in", Region: "Coastal ", Commodity: "Rice"}, "IN|coastal|rice"},
+		{"missing region", SignalEvent{Country: "IN", Commodity: "rice"}, "IN|global|rice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal := tt.in
+			signal.Enrich(time.Now())
+			if got := signal.Key(); got != tt.want {
+				t.Fatalf("key = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrichDefaults(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signal := SignalEvent{Country: "IN", Commodity: "rice"}
+	signal.Enrich(now)
+	if signal.ID == "" || !signal.Timestamp.Equal(now) || signal.Severity != 1 || signal.Confidence != 0.6 || signal.Source != SourceNews || signal.MetricName != "composite_signal" {
+		t.Fatalf("enriched = %+v, want defaults", signal)
+	}
+
+	set := SignalEvent{ID: "s1", Country: "IN", Commodity: "rice", Severity: 7, Confidence: 0.9, Source: SourceWeather}
+	set.Enrich(now)
+	if set.ID != "s1" || set.Severity != 7 || set.Confidence != 0.9 || set.Source != SourceWeather {
+		t.Fatalf("enriched = %+v, want the producer's values kept", set)
+	}
+}
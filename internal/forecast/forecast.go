@@ -0,0 +1,187 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultHorizons are the forecast horizons, in steps, served by default.
+var DefaultHorizons = []int{6, 24, 72}
+
+// DefaultMaxGap is how many steps the last observation may lie before the
+// forecast time.
+const DefaultMaxGap = 3
+
+var ErrStale = errors.New("series has no recent observations")
+
+// Z80 gives an 80% prediction interval.
+const Z80 = 1.2816
+
+type Observation struct {
+	At    time.Time
+	Value float64
+}
+
+type Options struct {
+	// Step is the spacing the observations are resampled to.
+	Step     time.Duration
+	Horizons []int
+	Z        float64
+	// Threshold is the score whose crossing is reported; zero disables it.
+	Threshold float64
+	Min, Max  float64
+	// MaxGap is how many steps the last observation may lie before the
+	// forecast time; zero means DefaultMaxGap.
+	MaxGap int
+}
+
+type Point struct {
+	HorizonSteps int       `json:"horizon_steps"`
+	Timestamp    time.Time `json:"timestamp"`
+	Score        float64   `json:"score"`
+	Lower        float64   `json:"lower"`
+	Upper        float64   `json:"upper"`
+}
+
+type Forecast struct {
+	LastAt    time.Time `json:"last_at"`
+	LastScore float64   `json:"last_score"`
+	Model     Holt      `json:"model"`
+	Points    []Point   `json:"points"`
+	// BreachIn is the first step at which the point forecast reaches the
+	// threshold and BreachPossibleIn the first at which the upper bound
+	// does. Both are nil when the series is already above the threshold
+	// or does not reach it within the longest horizon.
+	BreachIn         *int `json:"breach_in_steps"`
+	BreachPossibleIn *int `json:"breach_possible_in_steps"`
+}
+
+// Resample turns irregular observations into one value per step from the
+// first observation to the last, carrying the last value across gaps. It
+// does not extend the series past the last observation: flat values there
+// would only make the fitted model look more certain than it is.
+func Resample(obs []Observation, step time.Duration) []float64 {
+	if len(obs) == 0 || step <= 0 {
+		return nil
+	}
+	sorted := append([]Observation(nil), obs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	start := sorted[0].At.Truncate(step)
+	end := sorted[len(sorted)-1].At.Truncate(step)
+
+	values := make([]float64, 0, int(end.Sub(start)/step)+1)
+	i := 0
+	last := sorted[0].Value
+	for t := start; !t.After(end); t = t.Add(step) {
+		sum, n := 0.0, 0
+		for i < len(sorted) && sorted[i].At.Before(t.Add(step)) {
+			sum += sorted[i].Value
+			n++
+			i++
+		}
+		if n > 0 {
+			last = sum / float64(n)
+		}
+		values = append(values, last)
+	}
+	return values
+}
+
+// Project fits a Holt model to the resampled observations and forecasts
+// each horizon from the last observation. It returns ErrStale when that
+// observation is more than MaxGap steps before end.
+func Project(obs []Observation, end time.Time, opts Options) (Forecast, error) {
+	if opts.Step <= 0 {
+		opts.Step = time.Hour
+	}
+	if len(opts.Horizons) == 0 {
+		opts.Horizons = DefaultHorizons
+	}
+	if opts.Z <= 0 {
+		opts.Z = Z80
+	}
+	if opts.Max <= opts.Min {
+		opts.Min, opts.Max = 0, 100
+	}
+	if opts.MaxGap <= 0 {
+		opts.MaxGap = DefaultMaxGap
+	}
+
+	values := Resample(obs, opts.Step)
+	model, err := FitHolt(values)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	anchor := latest(obs).Truncate(opts.Step)
+	if end.Truncate(opts.Step).Sub(anchor) > time.Duration(opts.MaxGap)*opts.Step {
+		return Forecast{}, ErrStale
+	}
+	f := Forecast{
+		LastAt:    anchor,
+		LastScore: round2(values[len(values)-1]),
+		Model:     model,
+		Points:    make([]Point, 0, len(opts.Horizons)),
+	}
+
+	longest := 0
+	for _, h := range opts.Horizons {
+		if h <= 0 {
+			continue
+		}
+		if h > longest {
+			longest = h
+		}
+		value, half := model.At(h, opts.Z)
+		f.Points = append(f.Points, Point{
+			HorizonSteps: h,
+			Timestamp:    anchor.Add(time.Duration(h) * opts.Step),
+			Score:        round2(clamp(value, opts.Min, opts.Max)),
+			Lower:        round2(clamp(value-half, opts.Min, opts.Max)),
+			Upper:        round2(clamp(value+half, opts.Min, opts.Max)),
+		})
+	}
+
+	if opts.Threshold > 0 && f.LastScore < opts.Threshold {
+		for h := 1; h <= longest; h++ {
+			value, half := model.At(h, opts.Z)
+			if f.BreachPossibleIn == nil && value+half >= opts.Threshold {
+				step := h
+				f.BreachPossibleIn = &step
+			}
+			if value >= opts.Threshold {
+				step := h
+				f.BreachIn = &step
+				break
+			}
+		}
+	}
+	return f, nil
+}
+
+func latest(obs []Observation) time.Time {
+	last := obs[0].At
+	for _, o := range obs[1:] {
+		if o.At.After(last) {
+			last = o.At
+		}
+	}
+	return last
+}
+
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,94 @@
+package forecast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func hourly(values ...float64) []Observation {
+	obs := make([]Observation, 0, len(values))
+	for i, v := range values {
+		obs = append(obs, Observation{At: t0.Add(time.Duration(i) * time.Hour), Value: v})
+	}
+	return obs
+}
+
+func TestResample(t *testing.T) {
+	tests := []struct {
+		name string
+		obs  []Observation
+		want []float64
+	}{
+		{"empty", nil, nil},
+		{"regular", hourly(10, 20, 30), []float64{10, 20, 30}},
+		{"unsorted", []Observation{{At: t0.Add(time.Hour), Value: 20}, {At: t0, Value: 10}}, []float64{10, 20}},
+		{"averages within a step", []Observation{{At: t0, Value: 10}, {At: t0.Add(30 * time.Minute), Value: 20}, {At: t0.Add(time.Hour), Value: 40}}, []float64{15, 40}},
+		{"carries across gaps", []Observation{{At: t0, Value: 10}, {At: t0.Add(3 * time.Hour), Value: 40}}, []float64{10, 10, 10, 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resample(tt.obs, time.Hour); !slices.Equal(got, tt.want) {
+				t.Fatalf("Resample = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	obs := hourly(10, 20, 30, 40, 50)
+	last := obs[len(obs)-1].At
+	tests := []struct {
+		name    string
+		end     time.Time
+		wantErr error
+	}{
+		{"at last observation", last, nil},
+		{"within max gap", last.Add(3*time.Hour + 30*time.Minute), nil},
+		{"stale", last.Add(4 * time.Hour), ErrStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Project(obs, tt.end, Options{Horizons: []int{1, 3}, Threshold: 75})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// The forecast starts from the last observation, not from end.
+			if !f.LastAt.Equal(last) || f.LastScore != 50 {
+				t.Fatalf("anchor = %s %v, want %s 50", f.LastAt, f.LastScore, last)
+			}
+			if p := f.Points[1]; p.Score != 80 || !p.Timestamp.Equal(last.Add(3*time.Hour)) {
+				t.Fatalf("3-step point = %+v", p)
+			}
+			if f.BreachIn == nil || *f.BreachIn != 3 {
+				t.Fatalf("breach in %v steps, want 3", f.BreachIn)
+			}
+		})
+	}
+}
+
+func TestProjectClampsAndSkipsBreach(t *testing.T) {
+	obs := hourly(70, 80, 90, 95)
+	f, err := Project(obs, obs[len(obs)-1].At, Options{Horizons: []int{24}, Threshold: 75})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := f.Points[0]; p.Score > 100 || p.Upper > 100 {
+		t.Fatalf("point %+v exceeds the 0-100 scale", p)
+	}
+	if f.BreachIn != nil || f.BreachPossibleIn != nil {
+		t.Fatal("breach reported for a series already above the threshold")
+	}
+}
+
+func TestProjectTooShort(t *testing.T) {
+	if _, err := Project(hourly(10, 20), t0, Options{}); !errors.Is(err, ErrTooShort) {
+		t.Fatalf("err = %v, want ErrTooShort", err)
+	}
+}
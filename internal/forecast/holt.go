@@ -0,0 +1,83 @@
+// Package forecast projects risk scores forward from their recent history.
+package forecast
+
+import (
+	"errors"
+	"math"
+)
+
+// MinPoints is the shortest history a model can be fitted to.
+const MinPoints = 3
+
+var ErrTooShort = errors.New("series is too short to forecast")
+
+// Holt is a fitted double exponential smoothing model (Holt's linear trend
+// method) over an evenly spaced series.
+type Holt struct {
+	Alpha float64 `json:"alpha"`
+	Beta  float64 `json:"beta"`
+	Level float64 `json:"level"`
+	Trend float64 `json:"trend"`
+	// Sigma is the standard deviation of the one-step-ahead errors seen
+	// while fitting.
+	Sigma float64 `json:"sigma"`
+	N     int     `json:"n"`
+}
+
+// FitHolt picks alpha and beta from a coarse grid by minimizing the sum of
+// squared one-step-ahead errors.
+func FitHolt(values []float64) (Holt, error) {
+	if len(values) < MinPoints {
+		return Holt{}, ErrTooShort
+	}
+
+	best := Holt{}
+	bestSSE := math.Inf(1)
+	for a := 1; a <= 9; a++ {
+		for b := 1; b <= 9; b++ {
+			model, sse := fitHolt(values, float64(a)/10, float64(b)/10)
+			if sse < bestSSE {
+				best, bestSSE = model, sse
+			}
+		}
+	}
+	return best, nil
+}
+
+func fitHolt(values []float64, alpha, beta float64) (Holt, float64) {
+	level := values[0]
+	trend := values[1] - values[0]
+	sse := 0.0
+	for _, v := range values[1:] {
+		predicted := level + trend
+		err := v - predicted
+		sse += err * err
+
+		prevLevel := level
+		level = alpha*v + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+	}
+
+	steps := float64(len(values) - 1)
+	return Holt{
+		Alpha: alpha,
+		Beta:  beta,
+		Level: level,
+		Trend: trend,
+		Sigma: math.Sqrt(sse / steps),
+		N:     len(values),
+	}, sse
+}
+
+// At returns the point forecast h steps ahead and the half-width of its
+// prediction interval for the given z value.
+func (m Holt) At(h int, z float64) (value, halfWidth float64) {
+	value = m.Level + float64(h)*m.Trend
+
+	variance := 1.0
+	for j := 1; j < h; j++ {
+		c := m.Alpha * (1 + float64(j)*m.Beta)
+		variance += c * c
+	}
+	return value, z * m.Sigma * math.Sqrt(variance)
+}
@@ -0,0 +1,76 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitHolt(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		wantTrend float64
+		wantLevel float64
+	}{
+		{"flat", []float64{40, 40, 40, 40, 40}, 0, 40},
+		{"linear rise", []float64{10, 20, 30, 40, 50}, 10, 50},
+		{"linear fall", []float64{80, 70, 60, 50}, -10, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := FitHolt(tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(model.Trend-tt.wantTrend) > 1e-9 || math.Abs(model.Level-tt.wantLevel) > 1e-9 {
+				t.Fatalf("level %v trend %v, want %v and %v", model.Level, model.Trend, tt.wantLevel, tt.wantTrend)
+			}
+			if model.Sigma > 1e-9 {
+				t.Fatalf("sigma = %v for an exact line, want 0", model.Sigma)
+			}
+			if model.N != len(tt.values) {
+				t.Fatalf("n = %d, want %d", model.N, len(tt.values))
+			}
+		})
+	}
+}
+
+func TestFitHoltTooShort(t *testing.T) {
+	if _, err := FitHolt([]float64{1, 2}); !errors.Is(err, ErrTooShort) {
+		t.Fatalf("err = %v, want ErrTooShort", err)
+	}
+}
+
+func TestHoltAt(t *testing.T) {
+	model := Holt{Alpha: 0.5, Beta: 0.5, Level: 50, Trend: 2, Sigma: 4}
+	tests := []struct {
+		h         int
+		wantValue float64
+		wantHalf  float64
+	}{
+		{1, 52, 4},
+		// variance 1 + (0.5*(1+0.5))^2
+		{2, 54, 4 * math.Sqrt(1+0.5625)},
+		{10, 70, 0},
+	}
+	for _, tt := range tests {
+		value, half := model.At(tt.h, 1)
+		if value != tt.wantValue {
+			t.Fatalf("At(%d) value = %v, want %v", tt.h, value, tt.wantValue)
+		}
+		if tt.wantHalf > 0 && math.Abs(half-tt.wantHalf) > 1e-9 {
+			t.Fatalf("At(%d) half-width = %v, want %v", tt.h, half, tt.wantHalf)
+		}
+	}
+
+	// Intervals widen with the horizon.
+	prev := 0.0
+	for h := 1; h <= 72; h++ {
+		_, half := model.At(h, Z80)
+		if half < prev {
+			t.Fatalf("half-width shrinks at step %d", h)
+		}
+		prev = half
+	}
+}
@@ -0,0 +1,100 @@
+package idempotency
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache remembers values by ID for a bounded time and a bounded number of
+// entries. The least recently stored entry is dropped first when full.
+type Cache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+type entry[V any] struct {
+	id        string
+	value     V
+	expiresAt time.Time
+}
+
+func New[V any](ttl time.Duration, maxEntries int) *Cache[V] {
+	return &Cache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (c *Cache[V]) Get(id string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		e := el.Value.(*entry[V])
+		if c.now().Before(e.expiresAt) {
+			return e.value, true
+		}
+		c.remove(el)
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Remember stores value under id unless a live entry already exists, in
+// which case the existing value is returned with ok set to false.
+func (c *Cache[V]) Remember(id string, value V) (existing V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if el, found := c.entries[id]; found {
+		e := el.Value.(*entry[V])
+		if now.Before(e.expiresAt) {
+			return e.value, false
+		}
+		c.remove(el)
+	}
+
+	c.entries[id] = c.order.PushBack(&entry[V]{id: id, value: value, expiresAt: now.Add(c.ttl)})
+	c.evict(now)
+	return value, true
+}
+
+func (c *Cache[V]) Forget(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *Cache[V]) evict(now time.Time) {
+	for el := c.order.Front(); el != nil; el = c.order.Front() {
+		e := el.Value.(*entry[V])
+		if now.Before(e.expiresAt) && (c.maxEntries <= 0 || len(c.entries) <= c.maxEntries) {
+			return
+		}
+		c.remove(el)
+	}
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[V]).id)
+}
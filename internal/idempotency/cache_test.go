@@ -0,0 +1,86 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestCache(ttl time.Duration, maxEntries int) (*Cache[int], *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	c := New[int](ttl, maxEntries)
+	c.now = clock.now
+	return c, clock
+}
+
+func TestRemember(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance time.Duration
+		wantOK  bool
+		want    int
+	}{
+		{"retry within ttl", time.Minute, false, 1},
+		{"retry at ttl", 10 * time.Minute, true, 2},
+		{"retry after ttl", time.Hour, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clock := newTestCache(10*time.Minute, 0)
+			if _, ok := c.Remember("a", 1); !ok {
+				t.Fatal("first Remember reported a duplicate")
+			}
+			clock.t = clock.t.Add(tt.advance)
+			got, ok := c.Remember("a", 2)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("Remember = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetExpires(t *testing.T) {
+	c, clock := newTestCache(time.Minute, 0)
+	c.Remember("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get = %d, %v, want 1, true", v, ok)
+	}
+	clock.t = clock.t.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expired entry returned")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len = %d after expiry, want 0", c.Len())
+	}
+}
+
+func TestForget(t *testing.T) {
+	c, _ := newTestCache(time.Minute, 0)
+	c.Remember("a", 1)
+	c.Forget("a")
+	if _, ok := c.Remember("a", 2); !ok {
+		t.Fatal("forgotten ID still reported as a duplicate")
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	c, clock := newTestCache(time.Hour, 2)
+	for i, id := range []string{"a", "b", "c"} {
+		clock.t = clock.t.Add(time.Second)
+		c.Remember(id, i)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", c.Len())
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("oldest entry kept past the limit")
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, ok := c.Get(id); !ok {
+			t.Fatalf("entry %s evicted", id)
+		}
+	}
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+// NewWriter hashes message keys onto partitions, so every message for a key
+// lands on the same partition and is consumed by the same instance.
 func NewWriter(brokers []string, topic string) *kafka.Writer {
 	return &kafka.Writer{
 		Addr:         kafka.TCP(brokers...),
 		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     &kafka.Hash{},
 		BatchTimeout: 250 * time.Millisecond,
 		RequiredAcks: kafka.RequireOne,
 		Async:        false,
@@ -31,17 +33,44 @@ func NewReader(brokers []string, topic, groupID string) *kafka.Reader {
 	})
 }
 
+// NewCoReader consumes several topics in one group. The range balancer hands
+// the same partition number of every topic to the same member, so topics with
+// equal partition counts that are keyed alike reach the same instance.
+func NewCoReader(brokers []string, topics []string, groupID string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		GroupTopics:    topics,
+		GroupID:        groupID,
+		GroupBalancers: []kafka.GroupBalancer{kafka.RangeGroupBalancer{}},
+		MinBytes:       1,
+		MaxBytes:       10e6,
+		CommitInterval: time.Second,
+		MaxWait:        time.Second,
+	})
+}
+
 func PublishJSON(ctx context.Context, writer *kafka.Writer, key string, payload any) error {
-	body, err := json.Marshal(payload)
+	msg, err := EncodeJSON(key, payload)
 	if err != nil {
 		return err
 	}
 
-	return writer.WriteMessages(ctx, kafka.Message{
+	return writer.WriteMessages(ctx, msg)
+}
+
+// EncodeJSON builds the message PublishJSON would write, for callers that
+// publish several messages in one WriteMessages call.
+func EncodeJSON(key string, payload any) (kafka.Message, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	return kafka.Message{
 		Key:   []byte(key),
 		Value: body,
 		Time:  time.Now().UTC(),
-	})
+	}, nil
 }
 
 func ParseMessageJSON[T any](msg kafka.Message) (T, error) {
@@ -0,0 +1,178 @@
+package risk
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const maxActions = 5
+
+//go:embed defaults/actions.json
+var defaultActionRules []byte
+
+// ActionCondition restricts when a rule fires. Empty lists match anything;
+// MinScore is inclusive and MaxScore exclusive, with zero MaxScore meaning
+// no upper bound.
+type ActionCondition struct {
+	Commodities []string                 `json:"commodities,omitempty"`
+	Countries   []string                 `json:"countries,omitempty"`
+	SourcesAny  []contracts.SignalSource `json:"sources_any,omitempty"`
+	MinScore    float64                  `json:"min_score,omitempty"`
+	MaxScore    float64                  `json:"max_score,omitempty"`
+	Trends      []string                 `json:"trends,omitempty"`
+}
+
+type ActionTemplate struct {
+	Code            string `json:"code"`
+	Text            string `json:"text"`
+	OwnerRole       string `json:"owner_role"`
+	DeadlineMinutes int    `json:"deadline_minutes"`
+}
+
+type ActionRule struct {
+	ID       string          `json:"id"`
+	Priority int             `json:"priority"`
+	When     ActionCondition `json:"when"`
+	Action   ActionTemplate  `json:"action"`
+}
+
+// ActionInput is what the rules see about a freshly scored key.
+type ActionInput struct {
+	Country   string
+	Commodity string
+	Score     float64
+	Trend     string
+	Sources   map[contracts.SignalSource]bool
+	At        time.Time
+}
+
+type ActionRules struct {
+	rules []ActionRule
+}
+
+func LoadActionRules(path string) (*ActionRules, error) {
+	body := defaultActionRules
+	if strings.TrimSpace(path) != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read action rules: %w", err)
+		}
+		body = raw
+	}
+
+	var doc struct {
+		Rules []ActionRule `json:"rules"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parse action rules: %w", err)
+	}
+	return NewActionRules(doc.Rules)
+}
+
+func NewActionRules(rules []ActionRule) (*ActionRules, error) {
+	seen := make(map[string]bool, len(rules))
+	out := make([]ActionRule, 0, len(rules))
+	for i, r := range rules {
+		if strings.TrimSpace(r.ID) == "" {
+			return nil, fmt.Errorf("action rule %d: id is required", i)
+		}
+		if seen[r.ID] {
+			return nil, fmt.Errorf("action rule %s: duplicate id", r.ID)
+		}
+		seen[r.ID] = true
+		if r.Action.Code == "" || r.Action.Text == "" {
+			return nil, fmt.Errorf("action rule %s: action code and text are required", r.ID)
+		}
+		for j := range r.When.Commodities {
+			r.When.Commodities[j] = strings.ToLower(strings.TrimSpace(r.When.Commodities[j]))
+		}
+		for j := range r.When.Countries {
+			r.When.Countries[j] = strings.ToUpper(strings.TrimSpace(r.When.Countries[j]))
+		}
+		out = append(out, r)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Priority > out[j].Priority
+	})
+	return &ActionRules{rules: out}, nil
+}
+
+// Evaluate returns the actions of every matching rule, highest priority
+// first, with at most one action per code.
+func (a *ActionRules) Evaluate(in ActionInput) []contracts.RecommendedAction {
+	actions := make([]contracts.RecommendedAction, 0, 2)
+	codes := make(map[string]bool)
+	for _, r := range a.rules {
+		if codes[r.Action.Code] || !r.When.matches(in) {
+			continue
+		}
+		codes[r.Action.Code] = true
+
+		action := contracts.RecommendedAction{
+			Code:      r.Action.Code,
+			Text:      r.Action.Text,
+			OwnerRole: r.Action.OwnerRole,
+			RuleID:    r.ID,
+		}
+		if r.Action.DeadlineMinutes > 0 {
+			action.Deadline = in.At.Add(time.Duration(r.Action.DeadlineMinutes) * time.Minute)
+		}
+		actions = append(actions, action)
+		if len(actions) == maxActions {
+			break
+		}
+	}
+	return actions
+}
+
+func (a *ActionRules) Rules() []ActionRule {
+	return append([]ActionRule(nil), a.rules...)
+}
+
+func (c ActionCondition) matches(in ActionInput) bool {
+	if in.Score < c.MinScore {
+		return false
+	}
+	if c.MaxScore > 0 && in.Score >= c.MaxScore {
+		return false
+	}
+	if len(c.Commodities) > 0 && !containsString(c.Commodities, in.Commodity) {
+		return false
+	}
+	if len(c.Countries) > 0 && !containsString(c.Countries, in.Country) {
+		return false
+	}
+	if len(c.Trends) > 0 && !containsString(c.Trends, in.Trend) {
+		return false
+	}
+	if len(c.SourcesAny) > 0 {
+		hit := false
+		for _, src := range c.SourcesAny {
+			if in.Sources[src] {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
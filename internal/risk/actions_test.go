@@ -0,0 +1,128 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func actionRule(id string, priority int, code string, when ActionCondition) ActionRule {
+	return ActionRule{
+		ID:       id,
+		Priority: priority,
+		When:     when,
+		Action:   ActionTemplate{Code: code, Text: "do " + code, OwnerRole: "ops"},
+	}
+}
+
+func TestNewActionRulesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []ActionRule
+		wantErr string
+	}{
+		{"missing id", []ActionRule{actionRule("", 0, "a", ActionCondition{})}, "id is required"},
+		{"duplicate id", []ActionRule{actionRule("r", 0, "a", ActionCondition{}), actionRule("r", 0, "b", ActionCondition{})}, "duplicate id"},
+		{"missing code", []ActionRule{actionRule("r", 0, "", ActionCondition{})}, "code and text are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewActionRules(tt.rules); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestActionConditions(t *testing.T) {
+	in := ActionInput{
+		Country:   "IN",
+		Commodity: "rice",
+		Score:     70,
+		Sources:   map[contracts.SignalSource]bool{contracts.SourceWeather: true},
+	}
+	tests := []struct {
+		name string
+		when ActionCondition
+		want bool
+	}{
+		{"empty matches", ActionCondition{}, true},
+		{"min score inclusive", ActionCondition{MinScore: 70}, true},
+		{"below min score", ActionCondition{MinScore: 70.5}, false},
+		{"max score exclusive", ActionCondition{MaxScore: 70}, false},
+		{"below max score", ActionCondition{MaxScore: 80}, true},
+		{"commodity normalized", ActionCondition{Commodities: []string{" Rice "}}, true},
+		{"other commodity", ActionCondition{Commodities: []string{"wheat"}}, false},
+		{"country normalized", ActionCondition{Countries: []string{"in"}}, true},
+		{"other country", ActionCondition{Countries: []string{"BR"}}, false},
+		{"any source", ActionCondition{SourcesAny: []contracts.SignalSource{contracts.SourceNews, contracts.SourceWeather}}, true},
+		{"missing source", ActionCondition{SourcesAny: []contracts.SignalSource{contracts.SourceNews}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := NewActionRules([]ActionRule{actionRule("r", 0, "a", tt.when)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(rules.Evaluate(in)) == 1; got != tt.want {
+				t.Fatalf("matched = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionEvaluateOrdering(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rules, err := NewActionRules([]ActionRule{
+		actionRule("low", 1, "notify", ActionCondition{}),
+		actionRule("high", 10, "reroute", ActionCondition{}),
+		actionRule("mid", 5, "notify", ActionCondition{}),
+		{ID: "timed", Priority: 3, Action: ActionTemplate{Code: "stock", Text: "check stock", DeadlineMinutes: 90}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := rules.Evaluate(ActionInput{Score: 50, At: at})
+	got := make([]string, 0, len(actions))
+	for _, a := range actions {
+		got = append(got, a.RuleID)
+	}
+	// Highest priority first, and only the first rule of each code.
+	if strings.Join(got, ",") != "high,mid,timed" {
+		t.Fatalf("rules = %v, want high,mid,timed", got)
+	}
+	if want := at.Add(90 * time.Minute); !actions[2].Deadline.Equal(want) {
+		t.Fatalf("deadline = %s, want %s", actions[2].Deadline, want)
+	}
+	if !actions[0].Deadline.IsZero() {
+		t.Fatalf("rule without a deadline got %s", actions[0].Deadline)
+	}
+}
+
+func TestActionEvaluateLimit(t *testing.T) {
+	rules := make([]ActionRule, 0, maxActions+2)
+	for i := range maxActions + 2 {
+		rules = append(rules, actionRule(fmt.Sprintf("r%d", i), i, fmt.Sprintf("code-%d", i), ActionCondition{}))
+	}
+	set, err := NewActionRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(set.Evaluate(ActionInput{})); got != maxActions {
+		t.Fatalf("actions = %d, want %d", got, maxActions)
+	}
+}
+
+func TestLoadDefaultActionRules(t *testing.T) {
+	rules, err := LoadActionRules("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules.Rules()) == 0 {
+		t.Fatal("embedded action rules are empty")
+	}
+}
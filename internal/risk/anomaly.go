@@ -0,0 +1,59 @@
+package risk
+
+import (
+	"math"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const FlagAnomaly = "anomaly"
+
+// AnomalyConfig controls per-key anomaly detection. Each key keeps an EWMA
+// baseline per source and metric; a signal more than Sigma standard
+// deviations above it is flagged and its contribution multiplied by Boost.
+// Detection is one-sided because every normalization profile maps higher
+// values to higher risk, so an abnormally low value is not boosted.
+type AnomalyConfig struct {
+	Sigma float64
+	Boost float64
+	Alpha float64
+}
+
+func (c AnomalyConfig) withDefaults() AnomalyConfig {
+	if c.Sigma <= 0 {
+		c.Sigma = 3
+	}
+	if c.Boost < 1 {
+		c.Boost = 1.5
+	}
+	if c.Alpha <= 0 || c.Alpha >= 1 {
+		c.Alpha = 0.1
+	}
+	return c
+}
+
+// detect scores the signal against the key's baseline for its metric and
+// then folds the value into that baseline. The caller must hold the shard
+// lock.
+func (c AnomalyConfig) detect(state *keyState, s contracts.SignalEvent) (anomaly bool, z float64) {
+	if math.IsNaN(s.MetricValue) || math.IsInf(s.MetricValue, 0) {
+		return false, 0
+	}
+	if state.baselines == nil {
+		state.baselines = make(map[string]*Baseline)
+	}
+
+	key := profileKey(s.Source, s.MetricName)
+	b, ok := state.baselines[key]
+	if !ok {
+		b = &Baseline{}
+		state.baselines[key] = b
+	}
+
+	if b.Warm() {
+		z = b.Z(s.MetricValue)
+		anomaly = z > c.Sigma
+	}
+	b.Observe(s.MetricValue, c.Alpha)
+	return anomaly, round2(z)
+}
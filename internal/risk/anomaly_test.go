@@ -0,0 +1,102 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// warmState returns a key whose baseline for weather/anomaly_index has seen
+// values alternating around 50 with a standard deviation of about 5.
+func warmState(c AnomalyConfig) *keyState {
+	state := &keyState{}
+	for i := range 40 {
+		value := 45.0
+		if i%2 == 1 {
+			value = 55
+		}
+		c.detect(state, contracts.SignalEvent{Source: contracts.SourceWeather, MetricName: "anomaly_index", MetricValue: value})
+	}
+	return state
+}
+
+func TestAnomalyDetect(t *testing.T) {
+	c := AnomalyConfig{Sigma: 3}.withDefaults()
+	tests := []struct {
+		name        string
+		value       float64
+		wantAnomaly bool
+		wantSign    float64
+	}{
+		{"within sigma", 60, false, 1},
+		{"spike above", 90, true, 1},
+		{"dip below", 10, false, -1},
+		{"non-finite", math.NaN(), false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := warmState(c)
+			anomaly, z := c.detect(state, contracts.SignalEvent{Source: contracts.SourceWeather, MetricName: "anomaly_index", MetricValue: tt.value})
+			if anomaly != tt.wantAnomaly {
+				t.Fatalf("anomaly = %v (z=%v), want %v", anomaly, z, tt.wantAnomaly)
+			}
+			if sign := signOf(z); sign != tt.wantSign {
+				t.Fatalf("z = %v, want sign %v", z, tt.wantSign)
+			}
+		})
+	}
+}
+
+func signOf(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func TestAnomalyDetectWarmup(t *testing.T) {
+	c := AnomalyConfig{}.withDefaults()
+	state := &keyState{}
+	for i := range baselineWarmup {
+		value := 10.0
+		if i == baselineWarmup-1 {
+			value = 1000
+		}
+		if anomaly, _ := c.detect(state, contracts.SignalEvent{Source: contracts.SourceNews, MetricName: "article_count", MetricValue: value}); anomaly {
+			t.Fatalf("signal %d flagged before the baseline was warm", i)
+		}
+	}
+}
+
+func TestProcessCountsAnomalies(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Options{Clock: ClockEvent, Anomaly: AnomalyConfig{Sigma: 3}})
+	for i := range 20 {
+		signal := clockSignal(string(rune('a'+i)), base.Add(time.Duration(i)*time.Minute))
+		signal.MetricValue = float64(45 + 10*(i%2))
+		if _, err := engine.Process(signal); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dip := clockSignal("dip", base.Add(time.Hour))
+	dip.MetricValue = 0
+	low, err := engine.Process(dip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spike := clockSignal("spike", base.Add(time.Hour+time.Minute))
+	spike.MetricValue = 100
+	high, err := engine.Process(spike)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if low.AnomalyCount != 0 || high.AnomalyCount != 1 {
+		t.Fatalf("anomaly counts = %d, %d, want 0, 1", low.AnomalyCount, high.AnomalyCount)
+	}
+}
@@ -0,0 +1,38 @@
+package risk
+
+import "math"
+
+const baselineWarmup = 10
+
+// Baseline is an exponentially weighted running mean and variance.
+type Baseline struct {
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	Count    int     `json:"count"`
+}
+
+func (b *Baseline) Warm() bool {
+	return b.Count >= baselineWarmup
+}
+
+// Z is the deviation of v from the mean in standard deviations, or zero
+// while the variance is still degenerate.
+func (b *Baseline) Z(v float64) float64 {
+	std := math.Sqrt(b.Variance)
+	if std == 0 {
+		return 0
+	}
+	return (v - b.Mean) / std
+}
+
+func (b *Baseline) Observe(v, alpha float64) {
+	if b.Count == 0 {
+		b.Mean = v
+		b.Count = 1
+		return
+	}
+	diff := v - b.Mean
+	b.Mean += alpha * diff
+	b.Variance = (1 - alpha) * (b.Variance + alpha*diff*diff)
+	b.Count++
+}
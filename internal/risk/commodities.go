@@ -0,0 +1,129 @@
+package risk
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const (
+	RelationSubstitute = "substitute"
+	RelationInput      = "input"
+	RelationTransport  = "transport"
+)
+
+//go:embed defaults/commodities.json
+var defaultCommodityGraph []byte
+
+// CommodityRelation says that a shock to From raises risk on To in the same
+// geography: To substitutes for From, consumes it as an input, or depends on
+// it for transport. Weight is the share of From's score passed on.
+type CommodityRelation struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Kind   string  `json:"kind"`
+	Weight float64 `json:"weight"`
+}
+
+type CommodityGraph struct {
+	Version   string              `json:"version"`
+	Relations []CommodityRelation `json:"relations"`
+
+	out map[string][]CommodityRelation
+}
+
+func LoadCommodityGraph(path string) (*CommodityGraph, error) {
+	body := defaultCommodityGraph
+	if strings.TrimSpace(path) != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read commodity graph: %w", err)
+		}
+		body = raw
+	}
+
+	var g CommodityGraph
+	if err := json.Unmarshal(body, &g); err != nil {
+		return nil, fmt.Errorf("parse commodity graph: %w", err)
+	}
+	return NewCommodityGraph(g.Version, g.Relations)
+}
+
+func NewCommodityGraph(version string, relations []CommodityRelation) (*CommodityGraph, error) {
+	g := &CommodityGraph{
+		Version:   version,
+		Relations: make([]CommodityRelation, 0, len(relations)),
+		out:       make(map[string][]CommodityRelation),
+	}
+	seen := make(map[string]bool, len(relations))
+	for i, r := range relations {
+		r.From = strings.ToLower(strings.TrimSpace(r.From))
+		r.To = strings.ToLower(strings.TrimSpace(r.To))
+		r.Kind = strings.ToLower(strings.TrimSpace(r.Kind))
+		if r.From == "" || r.To == "" {
+			return nil, fmt.Errorf("commodity relation %d: from and to are required", i)
+		}
+		if r.From == r.To {
+			return nil, fmt.Errorf("commodity relation %d: self relation on %s", i, r.From)
+		}
+		switch r.Kind {
+		case RelationSubstitute, RelationInput, RelationTransport:
+		default:
+			return nil, fmt.Errorf("commodity relation %d: unknown kind %q", i, r.Kind)
+		}
+		if r.Weight <= 0 || r.Weight > 1 || math.IsNaN(r.Weight) {
+			return nil, fmt.Errorf("commodity relation %d: weight must be in (0, 1]", i)
+		}
+		id := r.From + ">" + r.To
+		if seen[id] {
+			return nil, fmt.Errorf("commodity relation %d: duplicate %s -> %s", i, r.From, r.To)
+		}
+		seen[id] = true
+
+		g.Relations = append(g.Relations, r)
+		g.out[r.From] = append(g.out[r.From], r)
+	}
+	return g, nil
+}
+
+// Related returns the relations that touch a commodity on either side. An
+// empty commodity returns every relation.
+func (g *CommodityGraph) Related(commodity string) []CommodityRelation {
+	commodity = strings.ToLower(strings.TrimSpace(commodity))
+	out := make([]CommodityRelation, 0)
+	for _, r := range g.Relations {
+		if commodity == "" || r.From == commodity || r.To == commodity {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (e *Engine) Commodities() *CommodityGraph {
+	return e.commodities
+}
+
+// spillover passes a scored key's own risk to the related commodities in the
+// same country and region. Relations are followed one hop only.
+func (e *Engine) spillover(source contracts.RiskEvent, origin string) []Transfer {
+	transfers := make([]Transfer, 0)
+	for _, r := range e.commodities.out[strings.ToLower(source.Commodity)] {
+		transfers = append(transfers, Transfer{
+			Target: source.Country + "|" + source.Region + "|" + r.To,
+			Inbound: Inbound{
+				Origin: origin,
+				Score:  source.OwnScore,
+				Factor: r.Weight,
+				Kind:   r.Kind,
+				Hops:   1,
+				At:     source.Timestamp,
+			},
+		})
+	}
+	return transfers
+}
@@ -0,0 +1,98 @@
+package risk
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewCommodityGraphValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation CommodityRelation
+		wantErr  string
+	}{
+		{"missing to", CommodityRelation{From: "wheat", Kind: RelationSubstitute, Weight: 0.4}, "from and to are required"},
+		{"self relation", CommodityRelation{From: "wheat", To: " Wheat", Kind: RelationSubstitute, Weight: 0.4}, "self relation"},
+		{"unknown kind", CommodityRelation{From: "wheat", To: "rice", Kind: "sibling", Weight: 0.4}, "unknown kind"},
+		{"zero weight", CommodityRelation{From: "wheat", To: "rice", Kind: RelationSubstitute}, "weight must be in (0, 1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommodityGraph("test", []CommodityRelation{tt.relation})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	duplicate := CommodityRelation{From: "wheat", To: "rice", Kind: RelationSubstitute, Weight: 0.4}
+	if _, err := NewCommodityGraph("test", []CommodityRelation{duplicate, duplicate}); err == nil {
+		t.Fatal("duplicate relation accepted")
+	}
+}
+
+func TestCommodityGraphRelated(t *testing.T) {
+	graph, err := LoadCommodityGraph("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		commodity string
+		want      int
+	}{
+		{"", len(graph.Relations)},
+		{" Wheat ", 3},
+		{"insulin", 1},
+		{"copper", 0},
+	}
+	for _, tt := range tests {
+		if got := len(graph.Related(tt.commodity)); got != tt.want {
+			t.Fatalf("Related(%q) = %d relations, want %d", tt.commodity, got, tt.want)
+		}
+	}
+}
+
+// Spillover follows relations one hop in the same geography and, like
+// contagion, passes on only the key's own score.
+func TestSpillover(t *testing.T) {
+	graph, err := NewCommodityGraph("test", []CommodityRelation{
+		{From: "diesel", To: "wheat", Kind: RelationTransport, Weight: 0.5},
+		{From: "wheat", To: "rice", Kind: RelationSubstitute, Weight: 0.4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, _ := NewContagionGraph("none", nil)
+	engine := NewEngine(Options{Commodities: graph, Contagion: ContagionConfig{Graph: empty}})
+	now := time.Now().UTC()
+
+	diesel := clockSignal("diesel", now)
+	diesel.Commodity = "diesel"
+	diesel.Severity = 10
+	origin, err := engine.Process(diesel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfers := engine.Spread(origin)
+	if len(transfers) != 1 || transfers[0].Target != "BR|south|wheat" {
+		t.Fatalf("transfers = %+v, want one to BR|south|wheat", transfers)
+	}
+	if in := transfers[0].Inbound; in.Score != origin.OwnScore || in.Factor != 0.5 || in.Kind != RelationTransport {
+		t.Fatalf("inbound = %+v", in)
+	}
+
+	events := engine.Propagate(origin)
+	if len(events) != 1 {
+		t.Fatalf("propagated events = %d, want 1", len(events))
+	}
+	wheat := events[0]
+	if wheat.Commodity != "wheat" || wheat.RiskScore != round2(origin.OwnScore*0.5) {
+		t.Fatalf("wheat event = %s score %v", wheat.Commodity, wheat.RiskScore)
+	}
+	// The wheat key only holds inbound risk, so it has nothing of its own to
+	// pass on to rice.
+	if transfers := engine.Spread(wheat); len(transfers) != 0 {
+		t.Fatalf("spread from a contagion event = %+v", transfers)
+	}
+}
@@ -0,0 +1,137 @@
+package risk
+
+import (
+	"math"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// IntervalLevel is the coverage of the score intervals scorers report, and
+// z95 its two-sided normal quantile.
+const (
+	IntervalLevel = 0.95
+	z95           = 1.96
+)
+
+// IntervalScorer is implemented by scorers that can report how uncertain
+// their score is. Interval returns false when the samples say nothing
+// about the spread of the score.
+type IntervalScorer interface {
+	Scorer
+	Interval(samples []Sample) (contracts.ScoreInterval, bool)
+}
+
+// SampleExplainer is implemented by scorers whose per-signal score differs
+// from the default additive formula, so that breakdowns stay truthful.
+type SampleExplainer interface {
+	Explain(sample Sample) contracts.SignalBreakdown
+}
+
+func init() {
+	RegisterScorer(confidenceWeighted{})
+}
+
+// confidenceWeighted scores each signal from severity and value only and
+// uses confidence, source weight and decay as the weight of that signal in
+// the mean, instead of adding confidence into the signal score.
+type confidenceWeighted struct{}
+
+func (confidenceWeighted) Name() string { return "confidence_weighted" }
+
+func (m confidenceWeighted) Score(samples []Sample) (float64, []contracts.RiskContributor) {
+	if len(samples) == 0 {
+		return 0, nil
+	}
+
+	mean, _, _ := m.moments(samples)
+	scored := make([]contracts.RiskContributor, 0, len(samples))
+	for _, sample := range samples {
+		s := sample.Signal
+		scored = append(scored, contracts.RiskContributor{
+			Type:            contracts.ContributorSignal,
+			Source:          s.Source,
+			MetricName:      s.MetricName,
+			MetricValue:     s.MetricValue,
+			NormalizedValue: round2(sample.Normalized),
+			Score:           confidenceSignalScore(sample),
+			Weight:          round4(confidenceWeight(sample)),
+			Flags:           sample.Flags,
+			Anomaly:         sample.Anomaly,
+			ZScore:          sample.ZScore,
+		})
+	}
+	return round2(clamp(mean, 0, 100)), topContributors(scored, 5)
+}
+
+// Interval reports the standard error of the weighted mean using the
+// effective sample size of the weights, and a 95% interval around it. It
+// has no interval for windows with less than two effective signals.
+func (m confidenceWeighted) Interval(samples []Sample) (contracts.ScoreInterval, bool) {
+	mean, stderr, ok := m.moments(samples)
+	if !ok {
+		return contracts.ScoreInterval{}, false
+	}
+	return contracts.ScoreInterval{
+		StdErr: round2(stderr),
+		Lower:  round2(clamp(mean-z95*stderr, 0, 100)),
+		Upper:  round2(clamp(mean+z95*stderr, 0, 100)),
+		Level:  IntervalLevel,
+	}, true
+}
+
+func (confidenceWeighted) Explain(sample Sample) contracts.SignalBreakdown {
+	b := signalBreakdown(sample)
+	b.ConfidenceComponent = 0
+	b.Score = confidenceSignalScore(sample)
+	b.AggregationWeight = round4(confidenceWeight(sample))
+	return b
+}
+
+// moments returns the weighted mean and its standard error. ok is false when
+// the standard error is unknown: with no weight at all, or with a single
+// effective observation, which says nothing about spread.
+func (confidenceWeighted) moments(samples []Sample) (mean, stderr float64, ok bool) {
+	var sumW, sumW2, sumWS float64
+	for _, sample := range samples {
+		w := confidenceWeight(sample)
+		sumW += w
+		sumW2 += w * w
+		sumWS += w * confidenceSignalScore(sample)
+	}
+	if sumW == 0 {
+		return 0, 0, false
+	}
+	mean = sumWS / sumW
+
+	variance := 0.0
+	for _, sample := range samples {
+		d := confidenceSignalScore(sample) - mean
+		variance += confidenceWeight(sample) * d * d
+	}
+	variance /= sumW
+
+	effective := sumW * sumW / sumW2
+	if effective <= 1 {
+		return mean, 0, false
+	}
+	return mean, math.Sqrt(variance / (effective - 1)), true
+}
+
+// confidenceSignalScore rescales severity (up to 50) and value (up to 40)
+// onto 0-100 and applies the anomaly boost. Source weight is used as a
+// reliability weight rather than a multiplier.
+func confidenceSignalScore(sample Sample) float64 {
+	s := sample.Signal
+	severity := clamp(float64(s.Severity), 0, 10) * 5.0
+	valueComponent := clamp(sample.Normalized, 0, 100) / 100.0 * 40.0
+
+	score := (severity + valueComponent) * 100.0 / 90.0
+	if sample.Boost > 0 {
+		score *= sample.Boost
+	}
+	return clamp(score, 0, 100)
+}
+
+func confidenceWeight(sample Sample) float64 {
+	return sample.Weight * clamp(sample.Signal.Confidence, 0, 1) * math.Max(sample.SourceWeight, 0)
+}
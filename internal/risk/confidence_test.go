@@ -0,0 +1,84 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// confidenceSample scores (5*severity + 0.4*normalized) / 0.9 under the
+// confidence_weighted scorer.
+func confidenceSample(severity int, normalized, confidence float64) Sample {
+	return Sample{
+		Signal:       contracts.SignalEvent{Severity: severity, Confidence: confidence},
+		Normalized:   normalized,
+		Weight:       1,
+		SourceWeight: 1,
+	}
+}
+
+func TestConfidenceWeightedInterval(t *testing.T) {
+	low := confidenceSample(6, 15, 1)   // 40
+	high := confidenceSample(10, 10, 1) // 60
+	tests := []struct {
+		name      string
+		samples   []Sample
+		wantOK    bool
+		wantScore float64
+		want      contracts.ScoreInterval
+	}{
+		{"no samples", nil, false, 0, contracts.ScoreInterval{}},
+		{"single signal", []Sample{high}, false, 60, contracts.ScoreInterval{}},
+		{"no weight", []Sample{confidenceSample(6, 15, 0), confidenceSample(10, 10, 0)}, false, 0, contracts.ScoreInterval{}},
+		{"agreeing signals", []Sample{high, high}, true, 60, contracts.ScoreInterval{StdErr: 0, Lower: 60, Upper: 60, Level: IntervalLevel}},
+		// Equal weights: stderr = |60-40| / 2.
+		{"two signals", []Sample{low, high}, true, 50, contracts.ScoreInterval{StdErr: 10, Lower: 30.4, Upper: 69.6, Level: IntervalLevel}},
+		// One heavy signal and a barely weighted one: the effective sample
+		// size stays close to 1, so the interval is wide.
+		{"skewed weights", []Sample{low, confidenceSample(10, 10, 0.05)}, true, 40.95, contracts.ScoreInterval{StdErr: 13.49, Lower: 14.52, Upper: 67.38, Level: IntervalLevel}},
+	}
+	scorer := confidenceWeighted{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if score, _ := scorer.Score(tt.samples); score != tt.wantScore {
+				t.Fatalf("score = %v, want %v", score, tt.wantScore)
+			}
+			got, ok := scorer.Interval(tt.samples)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("Interval = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEngineIntervalUnknownForSingleSignal(t *testing.T) {
+	scorer, err := LookupScorer("confidence_weighted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine(Options{Scorer: scorer})
+	base := clockSignal("a", time.Now().UTC())
+	first, err := engine.Process(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Uncertainty != nil {
+		t.Fatalf("single signal interval = %+v, want none", *first.Uncertainty)
+	}
+
+	second := clockSignal("b", time.Now().UTC())
+	second.Severity = 9
+	event, err := engine.Process(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := event.Uncertainty
+	if iv == nil {
+		t.Fatal("no interval for two signals")
+	}
+	if iv.Lower > event.RiskScore || iv.Upper < event.RiskScore || math.IsNaN(iv.StdErr) {
+		t.Fatalf("interval %+v does not contain score %v", *iv, event.RiskScore)
+	}
+}
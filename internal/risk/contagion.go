@@ -0,0 +1,387 @@
+package risk
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const (
+	DefaultContagionMaxHops   = 2
+	DefaultContagionMinFactor = 0.05
+	DefaultContagionMinDelta  = 1.0
+
+	// anyCountry on both ends of an edge links the two regions within every
+	// country.
+	anyCountry = "*"
+)
+
+//go:embed defaults/contagion.json
+var defaultContagionGraph []byte
+
+// ContagionEdge links two country|region nodes. Weight is the share of the
+// origin's score passed to the target per hop; Commodities limits the edge
+// to the listed commodities when non-empty.
+type ContagionEdge struct {
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	Weight        float64  `json:"weight"`
+	Kind          string   `json:"kind"`
+	Bidirectional bool     `json:"bidirectional,omitempty"`
+	Commodities   []string `json:"commodities,omitempty"`
+}
+
+// ContagionGraph is the dependency graph of regions, ports and trade lanes
+// along which risk spills over to neighbouring keys.
+type ContagionGraph struct {
+	Version string          `json:"version"`
+	Edges   []ContagionEdge `json:"edges"`
+
+	out map[string][]ContagionEdge
+}
+
+// ContagionConfig controls propagation. A nil Graph uses the embedded
+// default graph.
+type ContagionConfig struct {
+	Graph *ContagionGraph
+	// MaxHops bounds how far risk travels from its origin and MinFactor
+	// drops paths whose accumulated weight falls below it.
+	MaxHops   int
+	MinFactor float64
+	// MinDelta suppresses propagated events that move the target score by
+	// less than this many points.
+	MinDelta float64
+}
+
+func (c ContagionConfig) withDefaults() ContagionConfig {
+	if c.Graph == nil {
+		c.Graph, _ = LoadContagionGraph("")
+	}
+	if c.MaxHops <= 0 {
+		c.MaxHops = DefaultContagionMaxHops
+	}
+	if c.MinFactor <= 0 {
+		c.MinFactor = DefaultContagionMinFactor
+	}
+	if c.MinDelta <= 0 {
+		c.MinDelta = DefaultContagionMinDelta
+	}
+	return c
+}
+
+// Inbound is risk a key receives from another key through the contagion
+// graph.
+type Inbound struct {
+	Origin string    `json:"origin"`
+	Score  float64   `json:"score"`
+	Factor float64   `json:"factor"`
+	Kind   string    `json:"kind"`
+	Hops   int       `json:"hops"`
+	At     time.Time `json:"at"`
+}
+
+func (in Inbound) value() float64 {
+	return in.Score * in.Factor
+}
+
+func LoadContagionGraph(path string) (*ContagionGraph, error) {
+	body := defaultContagionGraph
+	if strings.TrimSpace(path) != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read contagion graph: %w", err)
+		}
+		body = raw
+	}
+
+	var g ContagionGraph
+	if err := json.Unmarshal(body, &g); err != nil {
+		return nil, fmt.Errorf("parse contagion graph: %w", err)
+	}
+	return NewContagionGraph(g.Version, g.Edges)
+}
+
+func NewContagionGraph(version string, edges []ContagionEdge) (*ContagionGraph, error) {
+	g := &ContagionGraph{
+		Version: version,
+		Edges:   make([]ContagionEdge, 0, len(edges)),
+		out:     make(map[string][]ContagionEdge),
+	}
+	for i, edge := range edges {
+		from, err := normalizeNode(edge.From)
+		if err != nil {
+			return nil, fmt.Errorf("contagion edge %d: from: %w", i, err)
+		}
+		to, err := normalizeNode(edge.To)
+		if err != nil {
+			return nil, fmt.Errorf("contagion edge %d: to: %w", i, err)
+		}
+		if (strings.HasPrefix(from, anyCountry+"|")) != (strings.HasPrefix(to, anyCountry+"|")) {
+			return nil, fmt.Errorf("contagion edge %d: wildcard country must be used on both ends", i)
+		}
+		if from == to {
+			return nil, fmt.Errorf("contagion edge %d: self loop on %s", i, from)
+		}
+		if edge.Weight <= 0 || edge.Weight > 1 || math.IsNaN(edge.Weight) {
+			return nil, fmt.Errorf("contagion edge %d: weight must be in (0, 1]", i)
+		}
+		if strings.TrimSpace(edge.Kind) == "" {
+			edge.Kind = "link"
+		}
+		for j := range edge.Commodities {
+			edge.Commodities[j] = strings.ToLower(strings.TrimSpace(edge.Commodities[j]))
+		}
+		edge.From, edge.To = from, to
+
+		g.Edges = append(g.Edges, edge)
+		g.out[from] = append(g.out[from], edge)
+		if edge.Bidirectional {
+			reverse := edge
+			reverse.From, reverse.To = to, from
+			g.out[to] = append(g.out[to], reverse)
+		}
+	}
+	return g, nil
+}
+
+func normalizeNode(raw string) (string, error) {
+	parts := strings.Split(raw, "|")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("node %q must be country|region", raw)
+	}
+	country := strings.ToUpper(strings.TrimSpace(parts[0]))
+	region := strings.ToLower(strings.TrimSpace(parts[1]))
+	if country == "" || region == "" {
+		return "", fmt.Errorf("node %q must be country|region", raw)
+	}
+	return country + "|" + region, nil
+}
+
+// reach is a key downstream of an origin together with the strongest path
+// that leads to it.
+type reach struct {
+	country string
+	region  string
+	factor  float64
+	hops    int
+	kind    string
+}
+
+// neighbours returns the edges leaving a node for the commodity, with
+// wildcard edges resolved to the node's country.
+func (g *ContagionGraph) neighbours(country, region, commodity string) []ContagionEdge {
+	var edges []ContagionEdge
+	for _, from := range []string{country + "|" + region, anyCountry + "|" + region} {
+		for _, edge := range g.out[from] {
+			if len(edge.Commodities) > 0 && !containsString(edge.Commodities, commodity) {
+				continue
+			}
+			if strings.HasPrefix(edge.To, anyCountry+"|") {
+				edge.To = country + edge.To[len(anyCountry):]
+			}
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// reachable walks the graph breadth first from an origin and keeps, for each
+// node, the path with the highest accumulated weight.
+func (g *ContagionGraph) reachable(country, region, commodity string, maxHops int, minFactor float64) []reach {
+	origin := country + "|" + region
+	best := map[string]reach{}
+	frontier := []reach{{country: country, region: region, factor: 1}}
+	for hop := 1; hop <= maxHops && len(frontier) > 0; hop++ {
+		next := make([]reach, 0)
+		for _, node := range frontier {
+			for _, edge := range g.neighbours(node.country, node.region, commodity) {
+				factor := node.factor * edge.Weight
+				if edge.To == origin || factor < minFactor {
+					continue
+				}
+				if prev, ok := best[edge.To]; ok && prev.factor >= factor {
+					continue
+				}
+				toCountry, toRegion, _ := strings.Cut(edge.To, "|")
+				kind := edge.Kind
+				if node.kind != "" {
+					kind = node.kind
+				}
+				r := reach{country: toCountry, region: toRegion, factor: factor, hops: hop, kind: kind}
+				best[edge.To] = r
+				next = append(next, r)
+			}
+		}
+		frontier = next
+	}
+
+	out := make([]reach, 0, len(best))
+	for _, r := range best {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].country+"|"+out[i].region < out[j].country+"|"+out[j].region
+	})
+	return out
+}
+
+func (e *Engine) Contagion() *ContagionGraph {
+	return e.contagion.Graph
+}
+
+// Transfer is risk passed from a scored key to one of its targets.
+type Transfer struct {
+	Target  string  `json:"target"`
+	Inbound Inbound `json:"inbound"`
+}
+
+// Spread lists the risk a freshly scored key passes to the keys connected
+// to it in the contagion graph and to related commodities in the same
+// geography, without applying it. Events produced by propagation are not
+// spread further; multi-hop spread is resolved here from the origin. Only
+// the key's own score is passed on, never the inbound risk it holds, so risk
+// does not echo back along bidirectional edges.
+func (e *Engine) Spread(source contracts.RiskEvent) []Transfer {
+	if source.Trigger == contracts.RiskTriggerContagion {
+		return nil
+	}
+
+	cfg := e.contagion
+	origin := source.Country + "|" + source.Region + "|" + source.Commodity
+	targets := cfg.Graph.reachable(strings.ToUpper(source.Country), strings.ToLower(source.Region), strings.ToLower(source.Commodity), cfg.MaxHops, cfg.MinFactor)
+
+	transfers := e.spillover(source, origin)
+	for _, t := range targets {
+		transfers = append(transfers, Transfer{
+			Target: t.country + "|" + t.region + "|" + source.Commodity,
+			Inbound: Inbound{
+				Origin: origin,
+				Score:  source.OwnScore,
+				Factor: round4(t.factor),
+				Kind:   t.kind,
+				Hops:   t.hops,
+				At:     source.Timestamp,
+			},
+		})
+	}
+	return transfers
+}
+
+// Propagate applies Spread to this engine and returns an event for every
+// target whose score moved by at least MinDelta. It suits a single engine
+// that holds every key; the risk-engine instead routes each Transfer to the
+// instance that owns its target and applies it there with Receive.
+func (e *Engine) Propagate(source contracts.RiskEvent) []contracts.RiskEvent {
+	events := make([]contracts.RiskEvent, 0)
+	for _, t := range e.Spread(source) {
+		if event, ok := e.Receive(t); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Receive applies a transfer to its target and rescores it. It returns false
+// when the target score moved by less than MinDelta.
+func (e *Engine) Receive(t Transfer) (contracts.RiskEvent, bool) {
+	return e.receive(t.Target, t.Inbound, time.Now().UTC())
+}
+
+// Targets lists the keys that Propagate would pass a key's risk to.
+func (e *Engine) Targets(country, region, commodity string) []string {
+	cfg := e.contagion
+	keys := make([]string, 0)
+	for _, t := range cfg.Graph.reachable(strings.ToUpper(country), strings.ToLower(region), strings.ToLower(commodity), cfg.MaxHops, cfg.MinFactor) {
+		keys = append(keys, t.country+"|"+t.region+"|"+commodity)
+	}
+	for _, r := range e.commodities.out[strings.ToLower(commodity)] {
+		keys = append(keys, country+"|"+region+"|"+r.To)
+	}
+	return keys
+}
+
+// receive records inbound risk on a target key and rescores it. A zero
+// inbound score withdraws the origin's earlier contribution.
+func (e *Engine) receive(key string, in Inbound, wall time.Time) (contracts.RiskEvent, bool) {
+	sh := e.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	state, ok := sh.keys[key]
+	if !ok {
+		if in.value() <= 0 {
+			return contracts.RiskEvent{}, false
+		}
+		state = sh.state(key)
+		state.country, state.region, state.commodity = SplitKey(key)
+	}
+
+	if in.value() <= 0 {
+		if _, had := state.inbound[in.Origin]; !had {
+			return contracts.RiskEvent{}, false
+		}
+		delete(state.inbound, in.Origin)
+	} else {
+		if state.inbound == nil {
+			state.inbound = make(map[string]Inbound)
+		}
+		state.inbound[in.Origin] = in
+	}
+
+	now := in.At
+	if e.clock == ClockWall || now.IsZero() {
+		now = wall
+	}
+	limits := e.LimitsFor(state.commodity)
+	e.trim(state, limits, now)
+
+	state.updatedAt = wall
+	state.dirty = true
+	state.drained = len(state.signals) == 0 && len(state.inbound) == 0
+	state.active = true
+
+	if state.scored {
+		candidate, _, _ := e.combined(state, e.samples(state.signals, now, e.policy.Current()))
+		if math.Abs(candidate-state.lastScore) < e.contagion.MinDelta {
+			return contracts.RiskEvent{}, false
+		}
+	}
+
+	event := e.score(state, limits, now)
+	event.ID = e.eventID(key, contracts.RiskTriggerContagion+"|"+in.Origin, now)
+	event.Trigger = contracts.RiskTriggerContagion
+	return event, true
+}
+
+// propagated turns the inbound risk held by a key into contributors and
+// returns the strongest inbound value.
+func propagated(inbound map[string]Inbound) (float64, []contracts.RiskContributor) {
+	if len(inbound) == 0 {
+		return 0, nil
+	}
+
+	peak := 0.0
+	contributors := make([]contracts.RiskContributor, 0, len(inbound))
+	for _, in := range inbound {
+		if v := in.value(); v > peak {
+			peak = v
+		}
+		contributors = append(contributors, contracts.RiskContributor{
+			Type:            contracts.ContributorPropagated,
+			Origin:          in.Origin,
+			MetricName:      "contagion_" + in.Kind,
+			MetricValue:     in.Score,
+			NormalizedValue: in.Score,
+			Score:           in.Score,
+			Weight:          in.Factor,
+		})
+	}
+	return round2(clamp(peak, 0, 100)), contributors
+}
@@ -0,0 +1,156 @@
+package risk
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func contagionEngine(t *testing.T, cfg ContagionConfig, edges ...ContagionEdge) *Engine {
+	t.Helper()
+	graph, err := NewContagionGraph("test", edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Graph = graph
+	return NewEngine(Options{Clock: ClockEvent, AllowedLateness: time.Hour, Contagion: cfg})
+}
+
+// regionSignal builds a signal for a commodity without commodity relations,
+// so that only contagion moves its risk.
+func regionSignal(id, region string, at time.Time, severity int) contracts.SignalEvent {
+	signal := clockSignal(id, at)
+	signal.Country = "IN"
+	signal.Region = region
+	signal.Commodity = "insulin"
+	signal.Severity = severity
+	return signal
+}
+
+func transferTo(transfers []Transfer, target string) (Transfer, bool) {
+	for _, t := range transfers {
+		if t.Target == target {
+			return t, true
+		}
+	}
+	return Transfer{}, false
+}
+
+func TestNewContagionGraphValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		edge ContagionEdge
+	}{
+		{"malformed node", ContagionEdge{From: "IN", To: "IN|west", Weight: 0.5}},
+		{"self loop", ContagionEdge{From: "IN|West", To: "in|west", Weight: 0.5}},
+		{"zero weight", ContagionEdge{From: "IN|east", To: "IN|west", Weight: 0}},
+		{"weight above one", ContagionEdge{From: "IN|east", To: "IN|west", Weight: 1.5}},
+		{"one-sided wildcard", ContagionEdge{From: "*|east", To: "IN|west", Weight: 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewContagionGraph("test", []ContagionEdge{tt.edge}); err == nil {
+				t.Fatal("invalid edge accepted")
+			}
+		})
+	}
+}
+
+// Graph nodes are matched in lower case whatever case the graph file uses,
+// and transfers land on the key that the target's own signals use.
+func TestSpreadReachesLiveKey(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := contagionEngine(t, ContagionConfig{}, ContagionEdge{From: "in|Coastal", To: "IN|METRO", Weight: 0.8})
+
+	if _, err := engine.Process(regionSignal("metro", "metro", base, 1)); err != nil {
+		t.Fatal(err)
+	}
+	origin, err := engine.Process(regionSignal("coast", "coastal", base.Add(time.Minute), 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := engine.Propagate(origin)
+	if len(events) != 1 || events[0].Region != "metro" {
+		t.Fatalf("propagated events = %+v, want one for metro", events)
+	}
+	if want := round2(origin.OwnScore * 0.8); events[0].RiskScore != want {
+		t.Fatalf("metro score = %v, want %v", events[0].RiskScore, want)
+	}
+	if got := len(engine.Keys()); got != 2 {
+		t.Fatalf("keys = %v, want the two live keys only", engine.Keys())
+	}
+}
+
+// A key passes on only its own score: risk it received over a bidirectional
+// edge does not come back to the origin.
+func TestSpreadBidirectionalNoEcho(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := contagionEngine(t, ContagionConfig{}, ContagionEdge{From: "IN|east", To: "IN|west", Weight: 0.8, Bidirectional: true})
+
+	east, err := engine.Process(regionSignal("east", "east", base, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Propagate(east)
+
+	west, err := engine.Process(regionSignal("west", "west", base.Add(time.Minute), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if west.RiskScore <= west.OwnScore {
+		t.Fatalf("west score %v does not carry inbound risk above its own %v", west.RiskScore, west.OwnScore)
+	}
+
+	back, ok := transferTo(engine.Spread(west), east.Country+"|east|"+east.Commodity)
+	if !ok {
+		t.Fatal("no transfer back to east")
+	}
+	if back.Inbound.Score != west.OwnScore {
+		t.Fatalf("transfer back carries %v, want west's own score %v", back.Inbound.Score, west.OwnScore)
+	}
+}
+
+func TestSpreadCutOff(t *testing.T) {
+	chain := []ContagionEdge{
+		{From: "IN|a", To: "IN|b", Weight: 0.5},
+		{From: "IN|b", To: "IN|c", Weight: 0.5},
+		{From: "IN|c", To: "IN|d", Weight: 0.5},
+	}
+	tests := []struct {
+		name string
+		cfg  ContagionConfig
+		want []string
+	}{
+		{"default hops", ContagionConfig{}, []string{"b", "c"}},
+		{"three hops", ContagionConfig{MaxHops: 3}, []string{"b", "c", "d"}},
+		{"min factor", ContagionConfig{MaxHops: 3, MinFactor: 0.3}, []string{"b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := contagionEngine(t, tt.cfg, chain...)
+			origin, err := engine.Process(regionSignal("a", "a", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), 8))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0)
+			factor := 1.0
+			for _, region := range []string{"b", "c", "d"} {
+				factor *= 0.5
+				tr, ok := transferTo(engine.Spread(origin), "IN|"+region+"|"+origin.Commodity)
+				if !ok {
+					continue
+				}
+				got = append(got, region)
+				if tr.Inbound.Factor != factor || tr.Inbound.Hops != len(got) {
+					t.Fatalf("%s: factor %v hops %d, want %v and %d", region, tr.Inbound.Factor, tr.Inbound.Hops, factor, len(got))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
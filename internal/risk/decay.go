@@ -0,0 +1,51 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type DecayMode string
+
+const (
+	DecayNone        DecayMode = "none"
+	DecayExponential DecayMode = "exponential"
+	DecayLinear      DecayMode = "linear"
+)
+
+// Decay weights a signal by its age at scoring time. Exponential decay halves
+// the weight every HalfLife; linear decay reaches 0.5 at HalfLife and zero at
+// twice HalfLife.
+type Decay struct {
+	Mode     DecayMode
+	HalfLife time.Duration
+}
+
+func ParseDecayMode(raw string) (DecayMode, error) {
+	switch mode := DecayMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "", DecayNone:
+		return DecayNone, nil
+	case DecayExponential, DecayLinear:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown decay mode %q", raw)
+	}
+}
+
+func (d Decay) Weight(age time.Duration) float64 {
+	if d.HalfLife <= 0 || age <= 0 {
+		return 1
+	}
+
+	ratio := age.Seconds() / d.HalfLife.Seconds()
+	switch d.Mode {
+	case DecayExponential:
+		return math.Pow(0.5, ratio)
+	case DecayLinear:
+		return clamp(1-ratio/2, 0, 1)
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,104 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestDecayWeight(t *testing.T) {
+	hour := time.Hour
+	tests := []struct {
+		name  string
+		decay Decay
+		age   time.Duration
+		want  float64
+	}{
+		{"none", Decay{Mode: DecayNone, HalfLife: hour}, 3 * hour, 1},
+		{"no half-life", Decay{Mode: DecayExponential}, 3 * hour, 1},
+		{"future signal", Decay{Mode: DecayExponential, HalfLife: hour}, -hour, 1},
+		{"exponential fresh", Decay{Mode: DecayExponential, HalfLife: hour}, 0, 1},
+		{"exponential one half-life", Decay{Mode: DecayExponential, HalfLife: hour}, hour, 0.5},
+		{"exponential two half-lives", Decay{Mode: DecayExponential, HalfLife: hour}, 2 * hour, 0.25},
+		{"linear half-way", Decay{Mode: DecayLinear, HalfLife: hour}, 30 * time.Minute, 0.75},
+		{"linear one half-life", Decay{Mode: DecayLinear, HalfLife: hour}, hour, 0.5},
+		{"linear two half-lives", Decay{Mode: DecayLinear, HalfLife: hour}, 2 * hour, 0},
+		{"linear past zero", Decay{Mode: DecayLinear, HalfLife: hour}, 5 * hour, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.decay.Weight(tt.age); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("Weight(%s) = %v, want %v", tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDecayMode(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    DecayMode
+		wantErr bool
+	}{
+		{"", DecayNone, false},
+		{"none", DecayNone, false},
+		{" Exponential ", DecayExponential, false},
+		{"LINEAR", DecayLinear, false},
+		{"sigmoid", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecayMode(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Fatalf("ParseDecayMode(%q) = %q, %v", tt.raw, got, err)
+		}
+	}
+}
+
+func decaySamples(weight float64, n int) []Sample {
+	samples := make([]Sample, 0, n)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range n {
+		samples = append(samples, Sample{
+			Signal: contracts.SignalEvent{
+				Timestamp:   base.Add(time.Duration(i) * time.Minute),
+				Source:      contracts.SourceNews,
+				MetricValue: 60,
+				Severity:    6,
+				Confidence:  0.5,
+			},
+			Weight:       weight,
+			SourceWeight: 1,
+		})
+	}
+	return samples
+}
+
+// Heavily decayed samples round to a zero display weight but still carry
+// their full score: a mean of identical signals is their score whatever the
+// weights.
+func TestWeightedAverageUsesUnroundedWeights(t *testing.T) {
+	want, _ := weightedAverage{}.Score(decaySamples(1, 3))
+	if got, _ := (weightedAverage{}).Score(decaySamples(0.00004, 3)); got != want || got == 0 {
+		t.Fatalf("stale score = %v, want %v", got, want)
+	}
+}
+
+func TestExponentialWeightedSeedsWithOldestScore(t *testing.T) {
+	ewma, err := LookupScorer("exponential_weighted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := weightedAverage{}.Score(decaySamples(1, 1))
+	for _, weight := range []float64{1, 0.5, 0.01} {
+		// A lone decayed signal is still the whole average, and identical
+		// later signals keep it there.
+		if got, _ := ewma.Score(decaySamples(weight, 1)); got != want {
+			t.Fatalf("weight %v: single sample score = %v, want %v", weight, got, want)
+		}
+		if got, _ := ewma.Score(decaySamples(weight, 4)); got != want {
+			t.Fatalf("weight %v: repeated sample score = %v, want %v", weight, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package risk
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProcessDuplicateIDs(t *testing.T) {
+	base := time.Now().UTC()
+	tests := []struct {
+		name    string
+		second  string
+		wantErr error
+	}{
+		{"same id", "a", ErrDuplicateSignal},
+		{"other id", "b", nil},
+		{"no id", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(Options{})
+			first := clockSignal("a", base)
+			if tt.second == "" {
+				first.ID = ""
+			}
+			if _, err := engine.Process(first); err != nil {
+				t.Fatal(err)
+			}
+			_, err := engine.Process(clockSignal(tt.second, base.Add(time.Second)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			wantDuplicates := uint64(0)
+			if tt.wantErr != nil {
+				wantDuplicates = 1
+			}
+			if got := engine.Stats().Duplicates; got != wantDuplicates {
+				t.Fatalf("duplicates = %d, want %d", got, wantDuplicates)
+			}
+		})
+	}
+}
+
+// An ID only deduplicates while its signal is in the window; once trimmed
+// the same ID is scored again.
+func TestProcessDuplicateAfterWindow(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Options{Clock: ClockEvent, AllowedLateness: time.Hour, Limits: Limits{Window: 10 * time.Minute}})
+	if _, err := engine.Process(clockSignal("a", base)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Process(clockSignal("b", base.Add(20*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Process(clockSignal("a", base.Add(21*time.Minute))); err != nil {
+		t.Fatalf("trimmed ID rejected: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package risk
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+type Stats struct {
+	Keys       int    `json:"keys"`
+	Shards     int    `json:"shards"`
+	Evictions  uint64 `json:"evictions_total"`
+	Duplicates uint64 `json:"duplicates_total"`
+}
+
+func (e *Engine) Stats() Stats {
+	keys := 0
+	for _, sh := range e.shards {
+		sh.mu.Lock()
+		keys += len(sh.keys)
+		sh.mu.Unlock()
+	}
+
+	return Stats{
+		Keys:       keys,
+		Shards:     len(e.shards),
+		Evictions:  atomic.LoadUint64(&e.evictions),
+		Duplicates: atomic.LoadUint64(&e.duplicates),
+	}
+}
+
+// EvictIdle drops keys that have not received a signal within the idle TTL
+// and, if the engine is still above its key limit, the least recently
+// updated keys until it fits. Dirty keys are kept until their state has been
+// persisted, so a later restore does not fall back to an older snapshot. It
+// returns the number of keys evicted.
+func (e *Engine) EvictIdle(now time.Time) int {
+	ttl := e.idleTTL
+	if ttl <= 0 {
+		ttl = e.MaxWindow()
+	}
+	cutoff := now.Add(-ttl)
+
+	type entry struct {
+		key       string
+		updatedAt time.Time
+	}
+
+	evicted := 0
+	pinned := 0
+	remaining := make([]entry, 0)
+	for _, sh := range e.shards {
+		sh.mu.Lock()
+		for key, state := range sh.keys {
+			if state.dirty {
+				pinned++
+				continue
+			}
+			if state.updatedAt.Before(cutoff) {
+				delete(sh.keys, key)
+				evicted++
+				continue
+			}
+			if e.maxKeys > 0 {
+				remaining = append(remaining, entry{key: key, updatedAt: state.updatedAt})
+			}
+		}
+		sh.mu.Unlock()
+	}
+
+	if excess := len(remaining) + pinned - e.maxKeys; e.maxKeys > 0 && excess > 0 {
+		sort.Slice(remaining, func(i, j int) bool {
+			return remaining[i].updatedAt.Before(remaining[j].updatedAt)
+		})
+		for _, en := range remaining[:min(excess, len(remaining))] {
+			sh := e.shardFor(en.key)
+			sh.mu.Lock()
+			if state, ok := sh.keys[en.key]; ok && !state.dirty && !state.updatedAt.After(en.updatedAt) {
+				delete(sh.keys, en.key)
+				evicted++
+			}
+			sh.mu.Unlock()
+		}
+	}
+
+	atomic.AddUint64(&e.evictions, uint64(evicted))
+	return evicted
+}
@@ -0,0 +1,66 @@
+package risk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictIdleKeepsDirtyKeys(t *testing.T) {
+	now := time.Now().UTC()
+	engine := NewEngine(Options{IdleTTL: time.Minute})
+	first, err := engine.Process(clockSignal("a", now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := clockSignal("a", now).Key()
+
+	later := now.Add(time.Hour)
+	if evicted := engine.EvictIdle(later); evicted != 0 || !engine.Has(key) {
+		t.Fatalf("evicted %d keys before the state was persisted", evicted)
+	}
+
+	snapshots := engine.DirtySnapshots()
+	if len(snapshots) != 1 {
+		t.Fatalf("dirty snapshots = %d, want 1", len(snapshots))
+	}
+	if evicted := engine.EvictIdle(later); evicted != 1 || engine.Has(key) {
+		t.Fatalf("evicted %d keys after the state was persisted, want 1", evicted)
+	}
+
+	// The key comes back from its snapshot and scores on from where it was.
+	engine.Restore(snapshots)
+	next, err := engine.Process(clockSignal("b", now.Add(time.Second)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.PreviousScore == nil || *next.PreviousScore != first.RiskScore {
+		t.Fatalf("previous score = %v, want %v", next.PreviousScore, first.RiskScore)
+	}
+	if next.SignalCount != 2 {
+		t.Fatalf("signals in window = %d, want 2", next.SignalCount)
+	}
+}
+
+func TestEvictIdleMaxKeysSkipsDirtyKeys(t *testing.T) {
+	now := time.Now().UTC()
+	engine := NewEngine(Options{IdleTTL: time.Hour, MaxKeys: 1})
+	for i, commodity := range []string{"wheat", "rice", "maize"} {
+		signal := clockSignal(commodity, now.Add(time.Duration(i)*time.Second))
+		signal.Commodity = commodity
+		if _, err := engine.Process(signal); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 {
+			engine.DirtySnapshots()
+		}
+	}
+
+	// wheat and rice were persisted, maize was not: the dirty key counts
+	// towards the limit but only the clean keys can make room for it.
+	if evicted := engine.EvictIdle(now); evicted != 2 {
+		t.Fatalf("evicted %d keys, want 2", evicted)
+	}
+	if keys := engine.Keys(); len(keys) != 1 || keys[0] != "BR|south|maize" {
+		t.Fatalf("keys left = %v, want the dirty maize key", keys)
+	}
+}
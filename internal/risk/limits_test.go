@@ -0,0 +1,60 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestLimitsFor(t *testing.T) {
+	engine := NewEngine(Options{
+		Limits: Limits{Window: time.Hour},
+		Overrides: map[string]Limits{
+			" Insulin ": {Window: 4 * time.Hour},
+			"diesel":    {HistoryCap: 10},
+		},
+	})
+	tests := []struct {
+		commodity string
+		want      Limits
+	}{
+		{"insulin", Limits{Window: 4 * time.Hour, HistoryCap: DefaultHistoryCap}},
+		{" INSULIN", Limits{Window: 4 * time.Hour, HistoryCap: DefaultHistoryCap}},
+		{"diesel", Limits{Window: time.Hour, HistoryCap: 10}},
+		{"wheat", Limits{Window: time.Hour, HistoryCap: DefaultHistoryCap}},
+	}
+	for _, tt := range tests {
+		if got := engine.LimitsFor(tt.commodity); got != tt.want {
+			t.Fatalf("LimitsFor(%s) = %+v, want %+v", tt.commodity, got, tt.want)
+		}
+	}
+}
+
+func TestCommodityHistoryCap(t *testing.T) {
+	engine := NewEngine(Options{Overrides: map[string]Limits{"insulin": {Window: 4 * time.Hour, HistoryCap: 2}}})
+	signal := func(commodity string) contracts.SignalEvent {
+		return contracts.SignalEvent{Source: contracts.SourceNews, Country: "US", Region: "global", Commodity: commodity, Severity: 5, Confidence: 0.5}
+	}
+
+	process := func(s contracts.SignalEvent) contracts.RiskEvent {
+		t.Helper()
+		scored, err := engine.Process(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scored
+	}
+
+	var insulin, wheat contracts.RiskEvent
+	for range 4 {
+		insulin = process(signal("insulin"))
+		wheat = process(signal("wheat"))
+	}
+	if len(insulin.Contributors) != 2 || insulin.WindowMinutes != 240 {
+		t.Fatalf("insulin contributors = %d window = %d, want 2 and 240", len(insulin.Contributors), insulin.WindowMinutes)
+	}
+	if len(wheat.Contributors) != 4 || wheat.WindowMinutes != 30 {
+		t.Fatalf("wheat contributors = %d window = %d, want 4 and 30", len(wheat.Contributors), wheat.WindowMinutes)
+	}
+}
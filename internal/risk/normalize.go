@@ -0,0 +1,240 @@
+package risk
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+type NormalizationMethod string
+
+const (
+	NormalizeMinMax NormalizationMethod = "minmax"
+	NormalizeZScore NormalizationMethod = "zscore"
+	NormalizeLog    NormalizationMethod = "log"
+)
+
+const (
+	FlagUnknownMetric   = "unknown_metric"
+	FlagBaselineWarming = "baseline_warming"
+	FlagNonFinite       = "non_finite_value"
+)
+
+//go:embed defaults/normalization.json
+var defaultNormalization []byte
+
+// NormalizationProfile maps one source/metric pair onto the 0-100 scale used
+// by signalScore. Source may be "*" to match any source.
+type NormalizationProfile struct {
+	Source contracts.SignalSource `json:"source"`
+	Metric string                 `json:"metric"`
+	Method NormalizationMethod    `json:"method"`
+	Min    float64                `json:"min"`
+	Max    float64                `json:"max"`
+	// Alpha is the EWMA smoothing factor of the rolling baseline and ZCap
+	// the deviation, in standard deviations, that maps to 0 or 100. Both
+	// only apply to zscore profiles.
+	Alpha float64 `json:"alpha,omitempty"`
+	ZCap  float64 `json:"z_cap,omitempty"`
+}
+
+type NormalizationConfig struct {
+	Profiles []NormalizationProfile `json:"profiles"`
+}
+
+// Normalizer resolves a profile for each signal. The rolling baselines that
+// zscore profiles compare against belong to each key's state, so they are
+// persisted with it and follow the key when its partition moves.
+type Normalizer struct {
+	profiles map[string]NormalizationProfile
+
+	mu      sync.Mutex
+	unknown map[string]uint64
+}
+
+func LoadNormalizer(path string) (*Normalizer, error) {
+	body := defaultNormalization
+	if strings.TrimSpace(path) != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read normalization config: %w", err)
+		}
+		body = raw
+	}
+
+	var cfg NormalizationConfig
+	if err := json.Unmarshal(body, &cfg); err != nil {
+		return nil, fmt.Errorf("parse normalization config: %w", err)
+	}
+	return NewNormalizer(cfg)
+}
+
+func NewNormalizer(cfg NormalizationConfig) (*Normalizer, error) {
+	n := &Normalizer{
+		profiles: make(map[string]NormalizationProfile, len(cfg.Profiles)),
+		unknown:  make(map[string]uint64),
+	}
+
+	for i, p := range cfg.Profiles {
+		p.Metric = strings.TrimSpace(p.Metric)
+		if p.Source == "" {
+			p.Source = "*"
+		}
+		if p.Metric == "" {
+			return nil, fmt.Errorf("normalization profile %d: metric is required", i)
+		}
+		switch p.Method {
+		case NormalizeMinMax, NormalizeLog:
+			if p.Max <= p.Min {
+				return nil, fmt.Errorf("normalization profile %s/%s: max must exceed min", p.Source, p.Metric)
+			}
+		case NormalizeZScore:
+			if p.Alpha <= 0 || p.Alpha >= 1 {
+				p.Alpha = 0.05
+			}
+			if p.ZCap <= 0 {
+				p.ZCap = 3
+			}
+		default:
+			return nil, fmt.Errorf("normalization profile %s/%s: unknown method %q", p.Source, p.Metric, p.Method)
+		}
+		n.profiles[profileKey(p.Source, p.Metric)] = p
+	}
+
+	return n, nil
+}
+
+// Normalize maps the signal's metric value onto 0-100, updating the key's
+// rolling baseline in baselines for zscore profiles. Metrics without a
+// profile fall back to clamping and are flagged as unknown.
+func (n *Normalizer) Normalize(s contracts.SignalEvent, baselines map[string]*Baseline) (float64, NormalizationMethod, []string) {
+	if math.IsNaN(s.MetricValue) || math.IsInf(s.MetricValue, 0) {
+		return 0, "", []string{FlagNonFinite}
+	}
+
+	p, ok := n.lookup(s.Source, s.MetricName)
+	if !ok {
+		n.mu.Lock()
+		n.unknown[profileKey(s.Source, s.MetricName)]++
+		n.mu.Unlock()
+		return clamp(s.MetricValue, 0, 100), "", []string{FlagUnknownMetric}
+	}
+
+	switch p.Method {
+	case NormalizeLog:
+		return logScale(s.MetricValue, p.Min, p.Max), p.Method, nil
+	case NormalizeZScore:
+		return zscore(p, s, baselines)
+	default:
+		return minMax(s.MetricValue, p.Min, p.Max), p.Method, nil
+	}
+}
+
+// Renormalize recomputes a value without touching baselines. It is used for
+// restored window entries that predate normalization.
+func (n *Normalizer) Renormalize(s contracts.SignalEvent, baselines map[string]*Baseline) (float64, NormalizationMethod, []string) {
+	p, ok := n.lookup(s.Source, s.MetricName)
+	if !ok {
+		return clamp(s.MetricValue, 0, 100), "", []string{FlagUnknownMetric}
+	}
+	if p.Method == NormalizeZScore {
+		b := baselines[baselineKey(p, s)]
+		if b == nil || !b.Warm() {
+			return minMax(s.MetricValue, p.Min, p.Max), p.Method, []string{FlagBaselineWarming}
+		}
+		return zToScale(b.Z(s.MetricValue), p.ZCap), p.Method, nil
+	}
+	return n.Normalize(s, nil)
+}
+
+// UnknownMetrics reports how often each unmapped source/metric pair was seen.
+func (n *Normalizer) UnknownMetrics() map[string]uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	out := make(map[string]uint64, len(n.unknown))
+	for k, v := range n.unknown {
+		out[k] = v
+	}
+	return out
+}
+
+func (n *Normalizer) Profiles() []NormalizationProfile {
+	out := make([]NormalizationProfile, 0, len(n.profiles))
+	for _, p := range n.profiles {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return profileKey(out[i].Source, out[i].Metric) < profileKey(out[j].Source, out[j].Metric)
+	})
+	return out
+}
+
+func (n *Normalizer) lookup(source contracts.SignalSource, metric string) (NormalizationProfile, bool) {
+	if p, ok := n.profiles[profileKey(source, metric)]; ok {
+		return p, true
+	}
+	if p, ok := n.profiles[profileKey("*", metric)]; ok {
+		return p, true
+	}
+	return NormalizationProfile{}, false
+}
+
+// zscore scores s against its baseline in baselines, which must not be nil,
+// and then folds the value into it.
+func zscore(p NormalizationProfile, s contracts.SignalEvent, baselines map[string]*Baseline) (float64, NormalizationMethod, []string) {
+	key := baselineKey(p, s)
+	b, ok := baselines[key]
+	if !ok {
+		b = &Baseline{}
+		baselines[key] = b
+	}
+
+	var value float64
+	var flags []string
+	if !b.Warm() {
+		value = minMax(s.MetricValue, p.Min, p.Max)
+		flags = []string{FlagBaselineWarming}
+	} else {
+		value = zToScale(b.Z(s.MetricValue), p.ZCap)
+	}
+	b.Observe(s.MetricValue, p.Alpha)
+
+	return value, p.Method, flags
+}
+
+func zToScale(z, zCap float64) float64 {
+	return clamp((z+zCap)/(2*zCap), 0, 1) * 100
+}
+
+func minMax(v, min, max float64) float64 {
+	if max <= min {
+		return clamp(v, 0, 100)
+	}
+	return clamp((v-min)/(max-min), 0, 1) * 100
+}
+
+func logScale(v, min, max float64) float64 {
+	if max <= min {
+		return clamp(v, 0, 100)
+	}
+	return math.Log1p(clamp(v, min, max)-min) / math.Log1p(max-min) * 100
+}
+
+func profileKey(source contracts.SignalSource, metric string) string {
+	return string(source) + "/" + metric
+}
+
+// baselineKey names a rolling baseline within a key's state. The state is
+// already scoped to one country, region and commodity, so one market's normal
+// does not define another's.
+func baselineKey(p NormalizationProfile, s contracts.SignalEvent) string {
+	return profileKey(s.Source, p.Metric)
+}
@@ -0,0 +1,127 @@
+package risk
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestNormalize(t *testing.T) {
+	norm, err := LoadNormalizer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		source     contracts.SignalSource
+		metric     string
+		value      float64
+		want       float64
+		wantMethod NormalizationMethod
+		wantFlag   string
+	}{
+		{"minmax", contracts.SourceShippingLane, "transit_delay_hours", 48, 50, NormalizeMinMax, ""},
+		{"minmax clamps", contracts.SourceShippingLane, "transit_delay_hours", 200, 100, NormalizeMinMax, ""},
+		{"wildcard source", contracts.SourceWeather, "anomaly_index", 30, 30, NormalizeMinMax, ""},
+		{"log at max", contracts.SourceWeather, "rainfall_mm", 500, 100, NormalizeLog, ""},
+		{"log at min", contracts.SourceWeather, "rainfall_mm", 0, 0, NormalizeLog, ""},
+		{"zscore warming", contracts.SourceNews, "article_count", 100, 50, NormalizeZScore, FlagBaselineWarming},
+		{"unknown metric", contracts.SourceNews, "mentions", 140, 100, "", FlagUnknownMetric},
+		{"non-finite", contracts.SourceNews, "article_count", math.Inf(1), 0, "", FlagNonFinite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal := contracts.SignalEvent{Source: tt.source, MetricName: tt.metric, MetricValue: tt.value}
+			got, method, flags := norm.Normalize(signal, make(map[string]*Baseline))
+			if math.Abs(got-tt.want) > 1e-9 || method != tt.wantMethod {
+				t.Fatalf("Normalize = %v, %q, want %v, %q", got, method, tt.want, tt.wantMethod)
+			}
+			if tt.wantFlag != "" && !slices.Contains(flags, tt.wantFlag) {
+				t.Fatalf("flags = %v, want %s", flags, tt.wantFlag)
+			}
+			if tt.wantFlag == "" && len(flags) != 0 {
+				t.Fatalf("unexpected flags %v", flags)
+			}
+		})
+	}
+}
+
+func articleSignal(id string, at time.Time, count float64) contracts.SignalEvent {
+	signal := clockSignal(id, at)
+	signal.Source = contracts.SourceNews
+	signal.MetricName = "article_count"
+	signal.MetricValue = count
+	return signal
+}
+
+// Zscore baselines belong to the key's state: a snapshot carries them to a
+// fresh engine, which then scores the next value against the warm baseline
+// instead of starting to warm up again.
+func TestNormalizationBaselinesSurviveRestore(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	warmed := NewEngine(Options{Clock: ClockEvent})
+	for i := range baselineWarmup {
+		if _, err := warmed.Process(articleSignal(string(rune('a'+i)), base.Add(time.Duration(i)*time.Minute), float64(20+i%3))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots := warmed.DirtySnapshots()
+	if len(snapshots) != 1 || len(snapshots[0].Norms) != 1 {
+		t.Fatalf("snapshot normalization baselines = %+v", snapshots)
+	}
+
+	next := articleSignal("next", base.Add(time.Hour), 90)
+	want, err := warmed.Process(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewEngine(Options{Clock: ClockEvent})
+	restored.Restore(snapshots)
+	got, err := restored.Process(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RiskScore != want.RiskScore {
+		t.Fatalf("restored score = %v, want %v", got.RiskScore, want.RiskScore)
+	}
+
+	// A key that was never persisted starts warming up again.
+	cold, err := NewEngine(Options{Clock: ClockEvent}).Process(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cold.RiskScore == want.RiskScore {
+		t.Fatalf("cold score %v matches the warm score", cold.RiskScore)
+	}
+}
+
+// Baselines are per key: a busy key does not warm up the baseline of
+// another key in the same country.
+func TestNormalizationBaselinesPerKey(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Options{Clock: ClockEvent})
+	for i := range baselineWarmup {
+		if _, err := engine.Process(articleSignal(string(rune('a'+i)), base.Add(time.Duration(i)*time.Minute), 20)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := articleSignal("other", base.Add(time.Hour), 90)
+	other.Commodity = "rice"
+	if _, err := engine.Process(other); err != nil {
+		t.Fatal(err)
+	}
+	for _, snap := range engine.DirtySnapshots() {
+		if snap.Key != other.Key() {
+			continue
+		}
+		if got := snap.Norms["news/article_count"].Count; got != 1 {
+			t.Fatalf("%s baseline has %d observations, want 1", snap.Key, got)
+		}
+		return
+	}
+	t.Fatalf("no snapshot for %s", other.Key())
+}
@@ -0,0 +1,166 @@
+package risk
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const FlagUnknownSource = "unknown_source"
+
+//go:embed defaults/weights.json
+var defaultWeightPolicy []byte
+
+// WeightPolicy is a versioned set of source weights with optional
+// per-commodity overrides.
+type WeightPolicy struct {
+	Version       string                                        `json:"version"`
+	DefaultWeight float64                                       `json:"default_weight"`
+	Sources       map[contracts.SignalSource]float64            `json:"sources"`
+	Commodities   map[string]map[contracts.SignalSource]float64 `json:"commodities"`
+}
+
+func ParseWeightPolicy(body []byte) (*WeightPolicy, error) {
+	var p WeightPolicy
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parse weight policy: %w", err)
+	}
+	if strings.TrimSpace(p.Version) == "" {
+		return nil, fmt.Errorf("weight policy: version is required")
+	}
+	if p.DefaultWeight <= 0 {
+		p.DefaultWeight = 1
+	}
+	for source, w := range p.Sources {
+		if w < 0 {
+			return nil, fmt.Errorf("weight policy %s: negative weight for %s", p.Version, source)
+		}
+	}
+
+	commodities := make(map[string]map[contracts.SignalSource]float64, len(p.Commodities))
+	for commodity, weights := range p.Commodities {
+		for source, w := range weights {
+			if w < 0 {
+				return nil, fmt.Errorf("weight policy %s: negative weight for %s/%s", p.Version, commodity, source)
+			}
+		}
+		commodities[strings.ToLower(strings.TrimSpace(commodity))] = weights
+	}
+	p.Commodities = commodities
+
+	return &p, nil
+}
+
+// Weight returns the weight for a source, preferring a commodity override.
+// known is false when neither the commodity nor the source table lists it.
+func (p *WeightPolicy) Weight(source contracts.SignalSource, commodity string) (weight float64, known bool) {
+	if overrides, ok := p.Commodities[commodity]; ok {
+		if w, ok := overrides[source]; ok {
+			return w, true
+		}
+	}
+	if w, ok := p.Sources[source]; ok {
+		return w, true
+	}
+	return p.DefaultWeight, false
+}
+
+// KnownSources lists the sources the policy weighs explicitly, in the
+// source table or in any commodity override, sorted by name.
+func (p *WeightPolicy) KnownSources() []contracts.SignalSource {
+	seen := make(map[contracts.SignalSource]bool, len(p.Sources))
+	out := make([]contracts.SignalSource, 0, len(p.Sources))
+	add := func(source contracts.SignalSource) {
+		if !seen[source] {
+			seen[source] = true
+			out = append(out, source)
+		}
+	}
+	for source := range p.Sources {
+		add(source)
+	}
+	for _, overrides := range p.Commodities {
+		for source := range overrides {
+			add(source)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// PolicyStore holds the active weight policy and reloads it from disk when
+// the file changes.
+type PolicyStore struct {
+	path    string
+	current atomic.Pointer[WeightPolicy]
+
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+func LoadPolicyStore(path string) (*PolicyStore, error) {
+	s := &PolicyStore{path: strings.TrimSpace(path)}
+	if s.path == "" {
+		p, err := ParseWeightPolicy(defaultWeightPolicy)
+		if err != nil {
+			return nil, err
+		}
+		s.current.Store(p)
+		return s, nil
+	}
+
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func NewPolicyStore(p *WeightPolicy) *PolicyStore {
+	s := &PolicyStore{}
+	s.current.Store(p)
+	return s
+}
+
+func (s *PolicyStore) Current() *WeightPolicy {
+	return s.current.Load()
+}
+
+// Reload re-reads the policy file if its modification time changed. A
+// policy that fails to parse leaves the active policy in place.
+func (s *PolicyStore) Reload() (bool, error) {
+	if s.path == "" {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, fmt.Errorf("stat weight policy: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) && s.current.Load() != nil {
+		return false, nil
+	}
+
+	body, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, fmt.Errorf("read weight policy: %w", err)
+	}
+	p, err := ParseWeightPolicy(body)
+	if err != nil {
+		return false, err
+	}
+
+	s.modTime = info.ModTime()
+	s.current.Store(p)
+	return true, nil
+}
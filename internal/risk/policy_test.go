@@ -0,0 +1,156 @@
+package risk
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestParseWeightPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"valid", `{"version":"v1","sources":{"news":0.5}}`, ""},
+		{"missing version", `{"sources":{"news":0.5}}`, "version is required"},
+		{"negative source weight", `{"version":"v1","sources":{"news":-1}}`, "negative weight for news"},
+		{"negative override", `{"version":"v1","commodities":{"wheat":{"news":-1}}}`, "negative weight for wheat/news"},
+		{"malformed", `{"version":`, "parse weight policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWeightPolicy([]byte(tt.body))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWeightPolicyWeight(t *testing.T) {
+	policy, err := ParseWeightPolicy([]byte(`{
+		"version": "v1",
+		"sources": {"news": 0.5, "weather": 1.2},
+		"commodities": {" Wheat ": {"news": 0.9}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		source    contracts.SignalSource
+		commodity string
+		want      float64
+		wantKnown bool
+	}{
+		{"source weight", contracts.SourceNews, "rice", 0.5, true},
+		{"commodity override", contracts.SourceNews, "wheat", 0.9, true},
+		{"override falls back to source", contracts.SourceWeather, "wheat", 1.2, true},
+		{"unknown source", contracts.SourcePriceSpike, "rice", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, known := policy.Weight(tt.source, tt.commodity)
+			if got != tt.want || known != tt.wantKnown {
+				t.Fatalf("Weight = %v, %v, want %v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func writePolicy(t *testing.T, path, body string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+	modTime := time.Now().Add(-time.Hour)
+	writePolicy(t, path, `{"version":"v1","sources":{"news":0.5}}`, modTime)
+
+	store, err := LoadPolicyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := store.Current().Version; v != "v1" {
+		t.Fatalf("version = %s, want v1", v)
+	}
+	if changed, err := store.Reload(); changed || err != nil {
+		t.Fatalf("unchanged file reloaded: %v, %v", changed, err)
+	}
+
+	modTime = modTime.Add(time.Minute)
+	writePolicy(t, path, `{"version":"v2","sources":{"news":0.7}}`, modTime)
+	if changed, err := store.Reload(); !changed || err != nil {
+		t.Fatalf("Reload = %v, %v, want a change", changed, err)
+	}
+	if w, _ := store.Current().Weight(contracts.SourceNews, "wheat"); w != 0.7 {
+		t.Fatalf("news weight = %v after reload, want 0.7", w)
+	}
+
+	// A broken edit keeps the last good policy active.
+	modTime = modTime.Add(time.Minute)
+	writePolicy(t, path, `{"sources":{"news":0.1}}`, modTime)
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("policy without a version accepted")
+	}
+	if v := store.Current().Version; v != "v2" {
+		t.Fatalf("version = %s after a failed reload, want v2", v)
+	}
+}
+
+func TestEngineTracksPolicyVersion(t *testing.T) {
+	first, _ := ParseWeightPolicy([]byte(`{"version":"v1","sources":{"weather":1}}`))
+	store := NewPolicyStore(first)
+	engine := NewEngine(Options{Policy: store})
+	base := time.Now().UTC()
+
+	before, err := engine.Process(clockSignal("a", base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := ParseWeightPolicy([]byte(`{"version":"v2","sources":{"weather":0.5}}`))
+	store.current.Store(second)
+	after, err := engine.Process(clockSignal("b", base.Add(time.Second)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.PolicyVersion != "v1" || after.PolicyVersion != "v2" {
+		t.Fatalf("policy versions = %s, %s, want v1, v2", before.PolicyVersion, after.PolicyVersion)
+	}
+	if after.RiskScore >= before.RiskScore {
+		t.Fatalf("score %v did not drop below %v with a lower weight", after.RiskScore, before.RiskScore)
+	}
+}
+
+func TestWeightPolicyKnownSources(t *testing.T) {
+	p, err := ParseWeightPolicy([]byte(`{
+		"version": "v1",
+		"sources": {"weather": 1.2, "news": 1},
+		"commodities": {"wheat": {"satellite": 1.5, "news": 0.8}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []contracts.SignalSource{"news", "satellite", "weather"}
+	if got := p.KnownSources(); !slices.Equal(got, want) {
+		t.Fatalf("known sources = %v, want %v", got, want)
+	}
+}
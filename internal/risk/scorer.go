@@ -0,0 +1,130 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const DefaultScorer = "weighted_average"
+
+// Sample is a signal in a key's window together with its effective weight
+// at scoring time, its metric value normalized onto 0-100, the source weight
+// from the active policy and the anomaly boost applied to its score.
+type Sample struct {
+	Signal       contracts.SignalEvent
+	Weight       float64
+	Normalized   float64
+	SourceWeight float64
+	Flags        []string
+	Anomaly      bool
+	ZScore       float64
+	Boost        float64
+}
+
+// Scorer turns the samples currently in a key's window into a single risk
+// score plus the contributors that explain it.
+type Scorer interface {
+	Name() string
+	Score(samples []Sample) (float64, []contracts.RiskContributor)
+}
+
+var (
+	scorersMu sync.RWMutex
+	scorers   = map[string]Scorer{}
+)
+
+func init() {
+	RegisterScorer(weightedAverage{})
+	RegisterScorer(maxContributor{})
+	RegisterScorer(exponentialWeighted{alpha: 0.3})
+}
+
+func RegisterScorer(s Scorer) {
+	scorersMu.Lock()
+	defer scorersMu.Unlock()
+	scorers[s.Name()] = s
+}
+
+func LookupScorer(name string) (Scorer, error) {
+	if name == "" {
+		name = DefaultScorer
+	}
+
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	s, ok := scorers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring model %q", name)
+	}
+	return s, nil
+}
+
+func ScorerNames() []string {
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	names := make([]string, 0, len(scorers))
+	for name := range scorers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type weightedAverage struct{}
+
+func (weightedAverage) Name() string { return DefaultScorer }
+
+func (weightedAverage) Score(samples []Sample) (float64, []contracts.RiskContributor) {
+	return aggregate(samples)
+}
+
+type maxContributor struct{}
+
+func (maxContributor) Name() string { return "max_contributor" }
+
+func (maxContributor) Score(samples []Sample) (float64, []contracts.RiskContributor) {
+	if len(samples) == 0 {
+		return 0, nil
+	}
+
+	scored := scoreContributors(samples)
+	peak := 0.0
+	for _, sample := range samples {
+		if v := signalScore(sample) * sample.Weight; v > peak {
+			peak = v
+		}
+	}
+
+	return round2(clamp(peak, 0, 100)), topContributors(scored, 5)
+}
+
+type exponentialWeighted struct {
+	alpha float64
+}
+
+func (exponentialWeighted) Name() string { return "exponential_weighted" }
+
+func (m exponentialWeighted) Score(samples []Sample) (float64, []contracts.RiskContributor) {
+	if len(samples) == 0 {
+		return 0, nil
+	}
+
+	ordered := append([]Sample(nil), samples...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Signal.Timestamp.Before(ordered[j].Signal.Timestamp)
+	})
+
+	scored := scoreContributors(ordered)
+	// Seed with the oldest score itself; decay only scales how much each
+	// later signal moves the average.
+	ewma := signalScore(ordered[0])
+	for _, sample := range ordered[1:] {
+		a := m.alpha * sample.Weight
+		ewma = a*signalScore(sample) + (1-a)*ewma
+	}
+
+	return round2(clamp(ewma, 0, 100)), topContributors(scored, 5)
+}
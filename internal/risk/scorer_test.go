@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestLookupScorer(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", DefaultScorer, false},
+		{"weighted_average", "weighted_average", false},
+		{"max_contributor", "max_contributor", false},
+		{"exponential_weighted", "exponential_weighted", false},
+		{"median", "", true},
+	}
+	for _, tt := range tests {
+		s, err := LookupScorer(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LookupScorer(%q) error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && s.Name() != tt.want {
+			t.Fatalf("LookupScorer(%q) = %s, want %s", tt.name, s.Name(), tt.want)
+		}
+	}
+
+	names := ScorerNames()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Fatalf("ScorerNames() = %v, want sorted", names)
+		}
+	}
+}
+
+// scorerSamples returns a full-weight news signal scoring 20 followed an
+// hour later by one scoring 80.
+func scorerSamples() []Sample {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(at time.Time, severity int, confidence, value float64) Sample {
+		return Sample{
+			Signal:       contracts.SignalEvent{Timestamp: at, Source: contracts.SourceNews, Severity: severity, Confidence: confidence, MetricValue: value},
+			Weight:       1,
+			Normalized:   value,
+			SourceWeight: 1,
+		}
+	}
+	return []Sample{sample(start, 2, 0.5, 0), sample(start.Add(time.Hour), 8, 1, 50)}
+}
+
+func TestScorerModels(t *testing.T) {
+	samples := scorerSamples()
+	reversed := []Sample{samples[1], samples[0]}
+	tests := []struct {
+		model   string
+		samples []Sample
+		want    float64
+	}{
+		{"weighted_average", samples, 50},
+		{"max_contributor", samples, 80},
+		{"exponential_weighted", samples, 38},
+		// The EWMA follows signal time, not window order.
+		{"exponential_weighted", reversed, 38},
+	}
+	for _, tt := range tests {
+		s, err := LookupScorer(tt.model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, contributors := s.Score(tt.samples)
+		if got != tt.want {
+			t.Fatalf("%s score = %v, want %v", tt.model, got, tt.want)
+		}
+		if len(contributors) != len(tt.samples) || contributors[0].Score < contributors[1].Score {
+			t.Fatalf("%s contributors = %+v, want both by descending score", tt.model, contributors)
+		}
+	}
+
+	if got, contributors := (weightedAverage{}).Score(nil); got != 0 || contributors != nil {
+		t.Fatalf("empty window scored %v %v", got, contributors)
+	}
+}
@@ -1,9 +1,13 @@
 package risk
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"sort"
-	"sync"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,127 +15,702 @@ import (
 	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
 )
 
+const (
+	DefaultWindow     = 30 * time.Minute
+	DefaultHistoryCap = 150
+)
+
+type Limits struct {
+	Window     time.Duration
+	HistoryCap int
+}
+
+type ClockMode string
+
+const (
+	ClockWall  ClockMode = "wall"
+	ClockEvent ClockMode = "event"
+)
+
+var (
+	ErrLateSignal      = errors.New("signal is older than the key watermark allows")
+	ErrDuplicateSignal = errors.New("signal id already in window")
+)
+
+func ParseClockMode(raw string) (ClockMode, error) {
+	switch mode := ClockMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "", ClockWall:
+		return ClockWall, nil
+	case ClockEvent:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown clock mode %q", raw)
+	}
+}
+
+type Options struct {
+	Limits
+	Scorer Scorer
+	Decay  Decay
+	// Normalizer maps raw metric values onto 0-100. Nil uses the embedded
+	// default profiles.
+	Normalizer *Normalizer
+	// Policy supplies source weights. Nil uses the embedded default policy.
+	Policy *PolicyStore
+	// Actions produces structured recommendations. Nil uses the embedded
+	// default rules.
+	Actions *ActionRules
+	// TrendThreshold is the score delta beyond which a key is classified as
+	// rising or falling.
+	TrendThreshold float64
+	Anomaly        AnomalyConfig
+	// Contagion spreads risk to keys connected in the dependency graph; see
+	// Propagate.
+	Contagion ContagionConfig
+	// Commodities relates commodities that substitute for or depend on each
+	// other. Nil uses the embedded default graph.
+	Commodities *CommodityGraph
+	// Overrides replaces the window and/or history cap for a commodity. Zero
+	// fields fall back to the engine-wide Limits.
+	Overrides map[string]Limits
+	// Clock selects whether windows are anchored to wall-clock time or to
+	// the newest signal timestamp seen for the key. In event mode, signals
+	// older than the key watermark minus AllowedLateness are rejected.
+	Clock           ClockMode
+	AllowedLateness time.Duration
+	// IdleTTL and MaxKeys bound the number of keys held in memory; see
+	// EvictIdle. A zero IdleTTL defaults to MaxWindow, a zero MaxKeys means
+	// no limit.
+	IdleTTL time.Duration
+	MaxKeys int
+	// Shards is the number of independently locked key partitions. Zero
+	// picks a default based on GOMAXPROCS.
+	Shards int
+}
+
+type keyState struct {
+	country   string
+	region    string
+	commodity string
+	signals   []WindowEntry
+	watermark time.Time
+	updatedAt time.Time
+	dirty     bool
+	drained   bool
+	// active is set once this engine processes a signal or inbound risk for
+	// the key, both of which arrive on the key's partition, or resumes it at
+	// startup. Keys that were only restored on demand are not rescored here.
+	active bool
+
+	scored       bool
+	lastScore    float64
+	lastScoredAt time.Time
+
+	baselines map[string]*Baseline
+	// norms are the rolling zscore normalization baselines, by source and
+	// metric.
+	norms   map[string]*Baseline
+	inbound map[string]Inbound
+}
+
 type Engine struct {
-	mu      sync.Mutex
-	window  time.Duration
-	history map[string][]contracts.SignalEvent
+	limits      Limits
+	overrides   map[string]Limits
+	scorer      Scorer
+	decay       Decay
+	norm        *Normalizer
+	policy      *PolicyStore
+	actions     *ActionRules
+	trendBand   float64
+	anomaly     AnomalyConfig
+	contagion   ContagionConfig
+	commodities *CommodityGraph
+	clock       ClockMode
+	lateness    time.Duration
+	idleTTL     time.Duration
+	maxKeys     int
+	shards      []*shard
+	evictions   uint64
+	duplicates  uint64
+	watermark   atomic.Int64
 }
 
-func NewEngine(window time.Duration) *Engine {
+func NewEngine(opts Options) *Engine {
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.HistoryCap <= 0 {
+		opts.HistoryCap = DefaultHistoryCap
+	}
+	if opts.Scorer == nil {
+		opts.Scorer = weightedAverage{}
+	}
+	if opts.Clock == "" {
+		opts.Clock = ClockWall
+	}
+	if opts.Normalizer == nil {
+		opts.Normalizer, _ = LoadNormalizer("")
+	}
+	if opts.Policy == nil {
+		opts.Policy, _ = LoadPolicyStore("")
+	}
+	if opts.Actions == nil {
+		opts.Actions, _ = LoadActionRules("")
+	}
+	if opts.Commodities == nil {
+		opts.Commodities, _ = LoadCommodityGraph("")
+	}
+	if opts.TrendThreshold <= 0 {
+		opts.TrendThreshold = DefaultTrendThreshold
+	}
+
+	overrides := make(map[string]Limits, len(opts.Overrides))
+	for commodity, o := range opts.Overrides {
+		if o.Window <= 0 {
+			o.Window = opts.Window
+		}
+		if o.HistoryCap <= 0 {
+			o.HistoryCap = opts.HistoryCap
+		}
+		overrides[strings.ToLower(strings.TrimSpace(commodity))] = o
+	}
+
 	return &Engine{
-		window:  window,
-		history: make(map[string][]contracts.SignalEvent),
+		limits:      opts.Limits,
+		overrides:   overrides,
+		scorer:      opts.Scorer,
+		decay:       opts.Decay,
+		norm:        opts.Normalizer,
+		policy:      opts.Policy,
+		actions:     opts.Actions,
+		trendBand:   opts.TrendThreshold,
+		anomaly:     opts.Anomaly.withDefaults(),
+		contagion:   opts.Contagion.withDefaults(),
+		commodities: opts.Commodities,
+		clock:       opts.Clock,
+		lateness:    opts.AllowedLateness,
+		idleTTL:     opts.IdleTTL,
+		maxKeys:     opts.MaxKeys,
+		shards:      newShards(opts.Shards),
+	}
+}
+
+// LimitsFor returns the window and history cap of commodity. Like the
+// override keys, the name is matched case-insensitively.
+func (e *Engine) LimitsFor(commodity string) Limits {
+	if o, ok := e.overrides[strings.ToLower(strings.TrimSpace(commodity))]; ok {
+		return o
+	}
+	return e.limits
+}
+
+// MaxWindow is the longest window across the default limits and commodity
+// overrides.
+func (e *Engine) MaxWindow() time.Duration {
+	longest := e.limits.Window
+	for _, o := range e.overrides {
+		if o.Window > longest {
+			longest = o.Window
+		}
 	}
+	return longest
+}
+
+func (e *Engine) Clock() ClockMode {
+	return e.clock
+}
+
+func (e *Engine) Scorer() Scorer {
+	return e.scorer
+}
+
+func (e *Engine) Normalizer() *Normalizer {
+	return e.norm
+}
+
+func (e *Engine) Policy() *PolicyStore {
+	return e.policy
 }
 
-func (e *Engine) Process(signal contracts.SignalEvent) contracts.RiskEvent {
-	now := time.Now().UTC()
+func (e *Engine) Actions() *ActionRules {
+	return e.actions
+}
+
+func (e *Engine) Process(signal contracts.SignalEvent) (contracts.RiskEvent, error) {
+	wall := time.Now().UTC()
+	if signal.Timestamp.IsZero() {
+		signal.Timestamp = wall
+	}
 	key := signal.Key()
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	sh := e.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	state := sh.state(key)
+	if signal.ID != "" && state.contains(signal.ID) {
+		atomic.AddUint64(&e.duplicates, 1)
+		return contracts.RiskEvent{}, fmt.Errorf("%w: key=%s id=%s", ErrDuplicateSignal, key, signal.ID)
+	}
+
+	now := wall
+	if e.clock == ClockEvent {
+		if !state.watermark.IsZero() && signal.Timestamp.Before(state.watermark.Add(-e.lateness)) {
+			return contracts.RiskEvent{}, fmt.Errorf("%w: key=%s ts=%s watermark=%s", ErrLateSignal, key, signal.Timestamp.Format(time.RFC3339), state.watermark.Format(time.RFC3339))
+		}
+		if signal.Timestamp.After(state.watermark) {
+			state.watermark = signal.Timestamp
+		}
+		now = state.watermark
+		e.advanceWatermark(now)
+	}
+
+	state.country, state.region, state.commodity = signal.Country, signal.Region, signal.Commodity
+	entry := e.entry(state, signal)
+	state.signals = insertByTime(state.signals, entry)
+	limits := e.LimitsFor(signal.Commodity)
+	e.trim(state, limits, now)
+
+	state.updatedAt = wall
+	state.dirty = true
+	state.drained = false
+	state.active = true
+
+	event := e.score(state, limits, now)
+	event.ID = e.eventID(key, signal.ID, now)
+	event.Trigger = contracts.RiskTriggerSignal
+	if entry.Anomaly {
+		event.Anomaly = anomalyOf(entry, event.Breakdown)
+	}
+	return event, nil
+}
 
-	entries := append(e.history[key], signal)
-	cutoff := now.Add(-e.window)
+// anomalyOf describes a flagged signal with the score it contributed, if it
+// is still in the window.
+func anomalyOf(entry WindowEntry, breakdown *contracts.RiskBreakdown) *contracts.SignalAnomaly {
+	a := &contracts.SignalAnomaly{
+		SignalID:    entry.ID,
+		Timestamp:   entry.Timestamp,
+		Source:      entry.Source,
+		MetricName:  entry.MetricName,
+		MetricValue: entry.MetricValue,
+		ZScore:      entry.ZScore,
+	}
+	if breakdown != nil {
+		for _, s := range breakdown.Signals {
+			if s.SignalID == entry.ID {
+				a.Score = s.Score
+				break
+			}
+		}
+	}
+	return a
+}
 
-	trimmed := entries[:0]
-	for _, s := range entries {
-		if s.Timestamp.IsZero() {
-			s.Timestamp = now
+// Rescore re-evaluates every key whose window lost signals to expiry since it
+// was last scored, so that risk decays when a key goes quiet. A key whose
+// window empties is emitted once with a zero score and then skipped until a
+// new signal arrives. Only keys this engine has processed a signal for, or
+// resumed at startup, are rescored. In event mode the engine-wide watermark is used as the clock
+// instead of now.
+func (e *Engine) Rescore(now time.Time) []contracts.RiskEvent {
+	if e.clock == ClockEvent {
+		now = e.Watermark()
+		if now.IsZero() {
+			return nil
 		}
+	}
+
+	events := make([]contracts.RiskEvent, 0)
+	for _, sh := range e.shards {
+		sh.mu.Lock()
+		for key, state := range sh.keys {
+			if state.drained || !state.active {
+				continue
+			}
+			limits := e.LimitsFor(state.commodity)
+			if e.trim(state, limits, now) == 0 {
+				continue
+			}
+
+			state.dirty = true
+			state.drained = len(state.signals) == 0 && len(state.inbound) == 0
+
+			event := e.score(state, limits, now)
+			event.ID = heartbeatID(key, state)
+			event.Trigger = contracts.RiskTriggerHeartbeat
+			events = append(events, event)
+		}
+		sh.mu.Unlock()
+	}
+	return events
+}
+
+// Watermark is the newest signal timestamp seen across all keys. It is only
+// maintained in event mode.
+func (e *Engine) Watermark() time.Time {
+	nanos := e.watermark.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
+
+func (e *Engine) advanceWatermark(ts time.Time) {
+	nanos := ts.UnixNano()
+	for {
+		current := e.watermark.Load()
+		if nanos <= current || e.watermark.CompareAndSwap(current, nanos) {
+			return
+		}
+	}
+}
+
+// trim drops signals and inbound contagion that fell out of the window, and
+// signals that exceed the history cap, and returns how many were removed.
+// The caller must hold the shard lock.
+func (e *Engine) trim(state *keyState, limits Limits, now time.Time) int {
+	before := len(state.signals)
+	cutoff := now.Add(-limits.Window)
+
+	trimmed := state.signals[:0]
+	for _, s := range state.signals {
 		if s.Timestamp.After(cutoff) {
 			trimmed = append(trimmed, s)
 		}
 	}
 
-	if len(trimmed) > 150 {
-		trimmed = trimmed[len(trimmed)-150:]
+	if len(trimmed) > limits.HistoryCap {
+		trimmed = trimmed[len(trimmed)-limits.HistoryCap:]
 	}
 
-	e.history[key] = trimmed
+	state.signals = trimmed
+	removed := before - len(trimmed)
+
+	for origin, in := range state.inbound {
+		if !in.At.After(cutoff) {
+			delete(state.inbound, origin)
+			removed++
+		}
+	}
+	return removed
+}
 
-	score, contributors := aggregate(trimmed)
+func (e *Engine) score(state *keyState, limits Limits, now time.Time) contracts.RiskEvent {
+	policy := e.policy.Current()
+	samples := e.samples(state.signals, now, policy)
+	score, own, contributors := e.combined(state, samples)
+
+	t := trend(state, score, now, e.trendBand)
+	state.scored = true
+	state.lastScore = score
+	state.lastScoredAt = now
+
+	sources := make(map[contracts.SignalSource]bool)
+	anomalies := 0
+	for _, en := range state.signals {
+		sources[en.Source] = true
+		if en.Anomaly {
+			anomalies++
+		}
+	}
+	actions := e.actions.Evaluate(ActionInput{
+		Country:   state.country,
+		Commodity: state.commodity,
+		Score:     score,
+		Trend:     t.Direction,
+		Sources:   sources,
+		At:        now,
+	})
 
 	return contracts.RiskEvent{
-		ID:                uuid.NewString(),
 		Timestamp:         now,
-		Country:           signal.Country,
-		Region:            signal.Region,
-		Commodity:         signal.Commodity,
+		Country:           state.country,
+		Region:            state.region,
+		Commodity:         state.commodity,
 		RiskScore:         score,
-		WindowMinutes:     int(e.window.Minutes()),
+		OwnScore:          own,
+		PreviousScore:     t.Previous,
+		ScoreDelta:        t.Delta,
+		VelocityPerHour:   t.VelocityPerHour,
+		Trend:             t.Direction,
+		WindowMinutes:     int(limits.Window.Minutes()),
 		Contributors:      contributors,
-		RecommendedAction: recommendation(score),
+		AnomalyCount:      anomalies,
+		SignalCount:       len(samples),
+		RecommendedAction: recommendation(score, actions),
+		Actions:           actions,
+		PolicyVersion:     policy.Version,
+		Uncertainty:       e.interval(samples, score, own),
+		Breakdown:         e.breakdown(samples, own, state.inbound),
+	}
+}
+
+// interval asks the scorer for the uncertainty of its score, if it reports
+// one and the window is large enough to estimate it. Inbound risk is a hard
+// floor on the final score, so it also floors both bounds.
+func (e *Engine) interval(samples []Sample, score, own float64) *contracts.ScoreInterval {
+	is, ok := e.scorer.(IntervalScorer)
+	if !ok || len(samples) == 0 {
+		return nil
+	}
+
+	iv, ok := is.Interval(samples)
+	if !ok {
+		return nil
+	}
+	if score > own {
+		iv.Lower = math.Max(iv.Lower, score)
+		iv.Upper = math.Max(iv.Upper, score)
+	}
+	return &iv
+}
+
+// combined scores the key's own window and folds in inbound contagion: the
+// key scores at least as high as its strongest propagated risk. own is the
+// scorer's result before inbound risk is applied.
+func (e *Engine) combined(state *keyState, samples []Sample) (score, own float64, contributors []contracts.RiskContributor) {
+	own, contributors = e.scorer.Score(samples)
+	if len(state.inbound) == 0 {
+		return own, own, contributors
+	}
+
+	score = own
+	inbound, propagatedContributors := propagated(state.inbound)
+	if inbound > score {
+		score = inbound
+	}
+	return score, own, topContributors(append(contributors, propagatedContributors...), 5)
+}
+
+// breakdown lists every sample that went into a score with its components,
+// unlike the contributors which keep only the strongest few.
+func (e *Engine) breakdown(samples []Sample, own float64, inbound map[string]Inbound) *contracts.RiskBreakdown {
+	b := &contracts.RiskBreakdown{
+		Scorer:      e.scorer.Name(),
+		SignalCount: len(samples),
+		OwnScore:    own,
+		Signals:     make([]contracts.SignalBreakdown, 0, len(samples)),
+		Inbound:     make([]contracts.InboundBreakdown, 0, len(inbound)),
+	}
+	explain := signalBreakdown
+	if ex, ok := e.scorer.(SampleExplainer); ok {
+		explain = ex.Explain
+	}
+	for _, sample := range samples {
+		b.Signals = append(b.Signals, explain(sample))
+	}
+
+	origins := make([]string, 0, len(inbound))
+	for origin := range inbound {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	for _, origin := range origins {
+		in := inbound[origin]
+		value := round2(clamp(in.value(), 0, 100))
+		if value > b.InboundScore {
+			b.InboundScore = value
+		}
+		b.Inbound = append(b.Inbound, contracts.InboundBreakdown{
+			Origin: in.Origin,
+			Kind:   in.Kind,
+			Score:  in.Score,
+			Factor: in.Factor,
+			Hops:   in.Hops,
+			Value:  value,
+		})
+	}
+	return b
+}
+
+// eventID is random in wall-clock mode. In event mode it is derived from the
+// key, triggering signal and scoring time so that a replay produces the same
+// IDs and the risk_events insert stays idempotent.
+func (e *Engine) eventID(key, trigger string, now time.Time) string {
+	if e.clock != ClockEvent || trigger == "" {
+		return uuid.NewString()
 	}
+	name := key + "|" + trigger + "|" + now.Format(time.RFC3339Nano)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
 }
 
-func aggregate(signals []contracts.SignalEvent) (float64, []contracts.RiskContributor) {
-	if len(signals) == 0 {
+// heartbeatID is derived from the key, its last signal update and what is
+// left of its window, so that two instances rescoring the same state after
+// a rebalance emit the same ID and the risk_events insert stays idempotent.
+// The caller must hold the shard lock.
+func heartbeatID(key string, state *keyState) string {
+	rest := "drained"
+	if len(state.signals) > 0 {
+		rest = state.signals[0].ID + "|" + strconv.Itoa(len(state.signals))
+	}
+	name := key + "|" + contracts.RiskTriggerHeartbeat + "|" + state.updatedAt.Format(time.RFC3339Nano) + "|" + rest
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+func (e *Engine) entry(state *keyState, signal contracts.SignalEvent) WindowEntry {
+	if state.norms == nil {
+		state.norms = make(map[string]*Baseline)
+	}
+	value, method, flags := e.norm.Normalize(signal, state.norms)
+	anomaly, z := e.anomaly.detect(state, signal)
+	if anomaly {
+		flags = append(flags, FlagAnomaly)
+	}
+	return WindowEntry{
+		SignalEvent:   signal,
+		Normalized:    value,
+		Normalization: method,
+		Flags:         flags,
+		Anomaly:       anomaly,
+		ZScore:        z,
+	}
+}
+
+func (s *keyState) contains(id string) bool {
+	for _, existing := range s.signals {
+		if existing.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func insertByTime(entries []WindowEntry, entry WindowEntry) []WindowEntry {
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Timestamp.After(entry.Timestamp)
+	})
+	entries = append(entries, WindowEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = entry
+	return entries
+}
+
+func (e *Engine) samples(entries []WindowEntry, now time.Time, policy *WeightPolicy) []Sample {
+	samples := make([]Sample, 0, len(entries))
+	for _, en := range entries {
+		sourceWeight, known := policy.Weight(en.Source, en.Commodity)
+		flags := en.Flags
+		if !known {
+			flags = append(append([]string(nil), flags...), FlagUnknownSource)
+		}
+		boost := 1.0
+		if en.Anomaly {
+			boost = e.anomaly.Boost
+		}
+		samples = append(samples, Sample{
+			Signal:       en.SignalEvent,
+			Weight:       e.decay.Weight(now.Sub(en.Timestamp)),
+			Normalized:   en.Normalized,
+			SourceWeight: sourceWeight,
+			Flags:        flags,
+			Anomaly:      en.Anomaly,
+			ZScore:       en.ZScore,
+			Boost:        boost,
+		})
+	}
+	return samples
+}
+
+func aggregate(samples []Sample) (float64, []contracts.RiskContributor) {
+	if len(samples) == 0 {
 		return 0, nil
 	}
 
-	scored := make([]contracts.RiskContributor, 0, len(signals))
+	scored := scoreContributors(samples)
 	total := 0.0
-	for _, s := range signals {
-		c := contracts.RiskContributor{
-			Source:      s.Source,
-			MetricName:  s.MetricName,
-			MetricValue: s.MetricValue,
-			Score:       signalScore(s),
-		}
-		total += c.Score
-		scored = append(scored, c)
+	weights := 0.0
+	for _, sample := range samples {
+		total += signalScore(sample) * sample.Weight
+		weights += sample.Weight
+	}
+	if weights == 0 {
+		return 0, topContributors(scored, 5)
 	}
 
-	avg := total / float64(len(scored))
+	avg := total / weights
 	score := clamp(avg, 0, 100)
 
-	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].Score > scored[j].Score
+	return round2(score), topContributors(scored, 5)
+}
+
+func scoreContributors(samples []Sample) []contracts.RiskContributor {
+	scored := make([]contracts.RiskContributor, 0, len(samples))
+	for _, sample := range samples {
+		s := sample.Signal
+		scored = append(scored, contracts.RiskContributor{
+			Type:            contracts.ContributorSignal,
+			Source:          s.Source,
+			MetricName:      s.MetricName,
+			MetricValue:     s.MetricValue,
+			NormalizedValue: round2(sample.Normalized),
+			Score:           signalScore(sample),
+			Weight:          round4(sample.Weight),
+			Flags:           sample.Flags,
+			Anomaly:         sample.Anomaly,
+			ZScore:          sample.ZScore,
+		})
+	}
+	return scored
+}
+
+func topContributors(scored []contracts.RiskContributor, n int) []contracts.RiskContributor {
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score*scored[i].Weight > scored[j].Score*scored[j].Weight
 	})
-	if len(scored) > 5 {
-		scored = scored[:5]
+	if len(scored) > n {
+		scored = scored[:n]
 	}
+	return scored
+}
 
-	return round2(score), scored
+func signalScore(sample Sample) float64 {
+	return signalBreakdown(sample).Score
 }
 
-func signalScore(s contracts.SignalEvent) float64 {
+// signalBreakdown scores one sample: severity (up to 50), confidence (up to
+// 20) and normalized value (up to 40) are summed, then multiplied by the
+// source weight and the anomaly boost and clamped to 0-100.
+func signalBreakdown(sample Sample) contracts.SignalBreakdown {
+	s := sample.Signal
 	severity := clamp(float64(s.Severity), 0, 10) * 5.0
 	confidence := clamp(s.Confidence, 0, 1) * 20.0
 
-	metricNorm := s.MetricValue
-	if metricNorm > 100 {
-		metricNorm = 100
-	}
-	if metricNorm < 0 {
-		metricNorm = 0
-	}
+	metricNorm := clamp(sample.Normalized, 0, 100)
 
 	valueComponent := (metricNorm / 100.0) * 40.0
 
-	weighted := (severity + confidence + valueComponent) * sourceWeight(s.Source)
-	return clamp(weighted, 0, 100)
-}
-
-func sourceWeight(source contracts.SignalSource) float64 {
-	switch source {
-	case contracts.SourceShippingLane:
-		return 1.25
-	case contracts.SourcePortCongestion:
-		return 1.30
-	case contracts.SourceWeather:
-		return 1.20
-	case contracts.SourcePriceSpike:
-		return 1.10
-	case contracts.SourceNews:
-		return 1.00
-	default:
-		return 1.00
+	boost := 1.0
+	if sample.Boost > 0 {
+		boost = sample.Boost
+	}
+	weighted := (severity + confidence + valueComponent) * sample.SourceWeight * boost
+
+	return contracts.SignalBreakdown{
+		SignalID:            s.ID,
+		Timestamp:           s.Timestamp,
+		Source:              s.Source,
+		MetricName:          s.MetricName,
+		MetricValue:         s.MetricValue,
+		NormalizedValue:     round2(sample.Normalized),
+		Severity:            s.Severity,
+		Confidence:          s.Confidence,
+		SeverityComponent:   round4(severity),
+		ConfidenceComponent: round4(confidence),
+		ValueComponent:      round4(valueComponent),
+		SourceWeight:        sample.SourceWeight,
+		AnomalyBoost:        boost,
+		Score:               clamp(weighted, 0, 100),
+		DecayWeight:         round4(sample.Weight),
+		Flags:               sample.Flags,
 	}
 }
 
-func recommendation(score float64) string {
+func recommendation(score float64, actions []contracts.RecommendedAction) string {
+	if len(actions) > 0 {
+		return actions[0].Text
+	}
+
 	switch {
 	case score >= 85:
 		return "Immediate intervention: pre-position inventory and activate cross-border backup routes."
@@ -157,3 +736,7 @@ func clamp(value, min, max float64) float64 {
 func round2(v float64) float64 {
 	return math.Round(v*100) / 100
 }
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
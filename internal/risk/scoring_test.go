@@ -0,0 +1,118 @@
+package risk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func clockSignal(id string, at time.Time) contracts.SignalEvent {
+	return contracts.SignalEvent{
+		ID:          id,
+		Timestamp:   at,
+		Source:      contracts.SourceWeather,
+		Country:     "BR",
+		Region:      "south",
+		Commodity:   "wheat",
+		MetricName:  "anomaly_index",
+		MetricValue: 50,
+		Severity:    5,
+		Confidence:  0.8,
+	}
+}
+
+func TestEventClockLateness(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		at      time.Time
+		wantErr error
+	}{
+		{"newer", base.Add(time.Minute), nil},
+		{"at watermark", base, nil},
+		{"within lateness", base.Add(-2 * time.Minute), nil},
+		{"at lateness bound", base.Add(-5 * time.Minute), nil},
+		{"too late", base.Add(-5*time.Minute - time.Second), ErrLateSignal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(Options{Clock: ClockEvent, AllowedLateness: 5 * time.Minute})
+			if _, err := engine.Process(clockSignal("first", base)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := engine.Process(clockSignal("second", tt.at))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventClockWatermark(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(Options{Clock: ClockEvent, AllowedLateness: time.Hour, Limits: Limits{Window: 30 * time.Minute}})
+
+	first, err := engine.Process(clockSignal("a", base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Timestamp.Equal(base) {
+		t.Fatalf("event timestamp = %s, want the signal time %s", first.Timestamp, base)
+	}
+
+	// A late signal inside the allowed lateness is scored at the watermark
+	// and does not move it back.
+	ahead := base.Add(20 * time.Minute)
+	if _, err := engine.Process(clockSignal("b", ahead)); err != nil {
+		t.Fatal(err)
+	}
+	late, err := engine.Process(clockSignal("c", base.Add(5*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !late.Timestamp.Equal(ahead) || !engine.Watermark().Equal(ahead) {
+		t.Fatalf("late event at %s, watermark %s, want %s", late.Timestamp, engine.Watermark(), ahead)
+	}
+
+	// The window is anchored on the watermark, not on the wall clock: the
+	// first signal drops out once the key's event time moves past it.
+	moved, err := engine.Process(clockSignal("d", base.Add(31*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.SignalCount != 3 {
+		t.Fatalf("signals in window = %d, want 3", moved.SignalCount)
+	}
+}
+
+func TestEventIDs(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	replay := func(clock ClockMode) string {
+		engine := NewEngine(Options{Clock: clock})
+		event, err := engine.Process(clockSignal("a", base))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return event.ID
+	}
+
+	if first, second := replay(ClockEvent), replay(ClockEvent); first != second {
+		t.Fatalf("event mode IDs differ on replay: %s, %s", first, second)
+	}
+	if first, second := replay(ClockWall), replay(ClockWall); first == second {
+		t.Fatalf("wall mode IDs repeat: %s", first)
+	}
+}
+
+func TestParseClockMode(t *testing.T) {
+	for raw, want := range map[string]ClockMode{"": ClockWall, "wall": ClockWall, " Event ": ClockEvent} {
+		if got, err := ParseClockMode(raw); err != nil || got != want {
+			t.Fatalf("ParseClockMode(%q) = %q, %v", raw, got, err)
+		}
+	}
+	if _, err := ParseClockMode("processing"); err == nil {
+		t.Fatal("unknown clock mode accepted")
+	}
+}
@@ -0,0 +1,50 @@
+package risk
+
+import (
+	"hash/fnv"
+	"runtime"
+	"sync"
+)
+
+// shard owns a disjoint subset of keys behind its own lock so that keys
+// hashing to different shards can be scored in parallel.
+type shard struct {
+	mu   sync.Mutex
+	keys map[string]*keyState
+}
+
+func newShards(n int) []*shard {
+	if n <= 0 {
+		n = defaultShardCount()
+	}
+	shards := make([]*shard, n)
+	for i := range shards {
+		shards[i] = &shard{keys: make(map[string]*keyState)}
+	}
+	return shards
+}
+
+func defaultShardCount() int {
+	n := runtime.GOMAXPROCS(0) * 4
+	if n < 16 {
+		n = 16
+	}
+	return n
+}
+
+func (e *Engine) shardFor(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return e.shards[h.Sum32()%uint32(len(e.shards))]
+}
+
+// state returns the key state, creating it when missing. The caller must
+// hold the shard lock.
+func (s *shard) state(key string) *keyState {
+	state, ok := s.keys[key]
+	if !ok {
+		state = &keyState{}
+		s.keys[key] = state
+	}
+	return state
+}
@@ -0,0 +1,117 @@
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func testSignal(i, key int, at time.Time) contracts.SignalEvent {
+	return contracts.SignalEvent{
+		ID:          fmt.Sprintf("sig-%d", i),
+		Timestamp:   at,
+		Source:      contracts.SourceNews,
+		Country:     "US",
+		Region:      "global",
+		Commodity:   fmt.Sprintf("commodity-%d", key),
+		MetricName:  "anomaly_index",
+		MetricValue: float64(i % 100),
+		Severity:    1 + i%10,
+		Confidence:  0.8,
+	}
+}
+
+func BenchmarkProcessParallel(b *testing.B) {
+	for _, shards := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			engine := NewEngine(Options{Shards: shards})
+			now := time.Now().UTC()
+			var seq atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					i := int(seq.Add(1))
+					if _, err := engine.Process(testSignal(i, i%5000, now)); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestConcurrentProcess(t *testing.T) {
+	const (
+		workers = 8
+		keys    = 5000
+		perKey  = 2
+	)
+	engine := NewEngine(Options{Limits: Limits{Window: time.Minute}, Shards: 16, MaxKeys: keys})
+	start := time.Now().UTC()
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < keys*perKey; i += workers {
+				if _, err := engine.Process(testSignal(i, i%keys, start)); err != nil {
+					t.Errorf("process %d: %v", i, err)
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			engine.Rescore(time.Now().UTC())
+			engine.EvictIdle(time.Now().UTC())
+			engine.MarkDirty(engine.DirtySnapshots())
+			engine.Stats()
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	background.Wait()
+
+	if got := engine.Stats().Keys; got != keys {
+		t.Fatalf("keys = %d, want %d", got, keys)
+	}
+	snapshots := engine.DirtySnapshots()
+	if len(snapshots) != keys {
+		t.Fatalf("dirty snapshots = %d, want %d", len(snapshots), keys)
+	}
+	for _, snap := range snapshots {
+		if len(snap.Signals) != perKey {
+			t.Fatalf("%s holds %d signals, want %d", snap.Key, len(snap.Signals), perKey)
+		}
+	}
+
+	// Once the window has passed every key drains on the next rescore and
+	// is then evicted as idle after its state is flushed.
+	later := start.Add(2 * time.Minute)
+	if got := len(engine.Rescore(later)); got != keys {
+		t.Fatalf("rescored %d keys, want %d", got, keys)
+	}
+	engine.DirtySnapshots()
+	if got := engine.EvictIdle(later.Add(time.Hour)); got != keys {
+		t.Fatalf("evicted %d keys, want %d", got, keys)
+	}
+}
@@ -0,0 +1,222 @@
+package risk
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// WindowEntry is a signal held in a key's window along with the normalized
+// metric value computed when it arrived.
+type WindowEntry struct {
+	contracts.SignalEvent
+	Normalized    float64             `json:"normalized_value"`
+	Normalization NormalizationMethod `json:"normalization,omitempty"`
+	Flags         []string            `json:"flags,omitempty"`
+	Anomaly       bool                `json:"anomaly,omitempty"`
+	ZScore        float64             `json:"z_score,omitempty"`
+}
+
+// KeySnapshot is the persisted window state of one country|region|commodity
+// key, used to carry scoring continuity across restarts.
+type KeySnapshot struct {
+	Key          string        `json:"key"`
+	Signals      []WindowEntry `json:"signals"`
+	Watermark    time.Time     `json:"watermark"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	LastScore    *float64      `json:"last_score,omitempty"`
+	LastScoredAt time.Time     `json:"last_scored_at"`
+	// Baselines are the per source/metric anomaly baselines of the key.
+	Baselines map[string]Baseline `json:"baselines,omitempty"`
+	// Norms are the per source/metric zscore normalization baselines.
+	Norms map[string]Baseline `json:"normalization_baselines,omitempty"`
+	// Inbound is the contagion risk the key holds, by origin.
+	Inbound map[string]Inbound `json:"inbound,omitempty"`
+}
+
+// DirtySnapshots returns the keys that changed since the previous call and
+// clears their dirty flag. Callers that fail to persist the result should
+// hand it back through MarkDirty.
+func (e *Engine) DirtySnapshots() []KeySnapshot {
+	out := make([]KeySnapshot, 0)
+	for _, sh := range e.shards {
+		sh.mu.Lock()
+		for key, state := range sh.keys {
+			if !state.dirty {
+				continue
+			}
+			state.dirty = false
+			out = append(out, state.snapshot(key))
+		}
+		sh.mu.Unlock()
+	}
+	return out
+}
+
+func (e *Engine) MarkDirty(snapshots []KeySnapshot) {
+	for _, snap := range snapshots {
+		sh := e.shardFor(snap.Key)
+		sh.mu.Lock()
+		if state, ok := sh.keys[snap.Key]; ok {
+			state.dirty = true
+		}
+		sh.mu.Unlock()
+	}
+}
+
+// Restore seeds the engine with previously persisted window state. Keys that
+// already hold newer state are left untouched.
+func (e *Engine) Restore(snapshots []KeySnapshot) int {
+	return e.restore(snapshots, false)
+}
+
+// Resume is Restore for the state an engine takes over at startup. The keys
+// are also rescored by the heartbeat, so a key that stays quiet after a
+// restart still decays instead of keeping its last score.
+func (e *Engine) Resume(snapshots []KeySnapshot) int {
+	return e.restore(snapshots, true)
+}
+
+func (e *Engine) restore(snapshots []KeySnapshot, active bool) int {
+	restored := 0
+	for _, snap := range snapshots {
+		sh := e.shardFor(snap.Key)
+		sh.mu.Lock()
+		if existing, ok := sh.keys[snap.Key]; !ok || existing.updatedAt.Before(snap.UpdatedAt) {
+			country, region, commodity := SplitKey(snap.Key)
+			norms := restoreBaselines(snap.Norms)
+			signals := append([]WindowEntry(nil), snap.Signals...)
+			for i := range signals {
+				if signals[i].Normalization == "" && len(signals[i].Flags) == 0 {
+					signals[i].Normalized, signals[i].Normalization, signals[i].Flags = e.norm.Renormalize(signals[i].SignalEvent, norms)
+				}
+			}
+			sh.keys[snap.Key] = &keyState{
+				country:   country,
+				region:    region,
+				commodity: commodity,
+				signals:   signals,
+				watermark: snap.Watermark,
+				updatedAt: snap.UpdatedAt,
+				baselines: restoreBaselines(snap.Baselines),
+				norms:     norms,
+				active:    active,
+			}
+			state := sh.keys[snap.Key]
+			if snap.LastScore != nil {
+				state.scored = true
+				state.lastScore = *snap.LastScore
+				state.lastScoredAt = snap.LastScoredAt
+			}
+			if len(snap.Inbound) > 0 {
+				state.inbound = make(map[string]Inbound, len(snap.Inbound))
+				for origin, in := range snap.Inbound {
+					state.inbound[origin] = in
+				}
+			}
+			if e.clock == ClockEvent && !snap.Watermark.IsZero() {
+				e.advanceWatermark(snap.Watermark)
+			}
+			restored++
+		}
+		sh.mu.Unlock()
+	}
+	return restored
+}
+
+// Has reports whether the engine holds state for key.
+func (e *Engine) Has(key string) bool {
+	sh := e.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	_, ok := sh.keys[key]
+	return ok
+}
+
+// Keys lists the keys held in memory.
+func (e *Engine) Keys() []string {
+	out := make([]string, 0)
+	for _, sh := range e.shards {
+		sh.mu.Lock()
+		for key := range sh.keys {
+			out = append(out, key)
+		}
+		sh.mu.Unlock()
+	}
+	return out
+}
+
+// Release drops keys whose persisted state is newer than the state held
+// here. versions maps keys to the updated_at of their persisted snapshot; a
+// newer one means another instance has processed the key since, so the copy
+// in memory is stale and must be restored again before it is scored. It
+// returns the number of keys dropped.
+func (e *Engine) Release(versions map[string]time.Time) int {
+	released := 0
+	for key, updatedAt := range versions {
+		sh := e.shardFor(key)
+		sh.mu.Lock()
+		if state, ok := sh.keys[key]; ok && state.updatedAt.Before(updatedAt) {
+			delete(sh.keys, key)
+			released++
+		}
+		sh.mu.Unlock()
+	}
+	return released
+}
+
+func (s *keyState) snapshot(key string) KeySnapshot {
+	snap := KeySnapshot{
+		Key:       key,
+		Signals:   append([]WindowEntry(nil), s.signals...),
+		Watermark: s.watermark,
+		UpdatedAt: s.updatedAt,
+	}
+	if s.scored {
+		last := s.lastScore
+		snap.LastScore = &last
+		snap.LastScoredAt = s.lastScoredAt
+	}
+	snap.Baselines = snapshotBaselines(s.baselines)
+	snap.Norms = snapshotBaselines(s.norms)
+	if len(s.inbound) > 0 {
+		snap.Inbound = make(map[string]Inbound, len(s.inbound))
+		for origin, in := range s.inbound {
+			snap.Inbound[origin] = in
+		}
+	}
+	return snap
+}
+
+func snapshotBaselines(in map[string]*Baseline) map[string]Baseline {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]Baseline, len(in))
+	for metric, b := range in {
+		out[metric] = *b
+	}
+	return out
+}
+
+func restoreBaselines(in map[string]Baseline) map[string]*Baseline {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]*Baseline, len(in))
+	for metric, b := range in {
+		out[metric] = &b
+	}
+	return out
+}
+
+// SplitKey splits a country|region|commodity state key into its parts.
+// Missing parts are empty.
+func SplitKey(key string) (country, region, commodity string) {
+	parts := strings.SplitN(key, "|", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	return parts[0], parts[1], parts[2]
+}
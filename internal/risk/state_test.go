@@ -0,0 +1,92 @@
+package risk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	opts := Options{Clock: ClockEvent, AllowedLateness: time.Hour, Limits: Limits{Window: time.Hour}}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	signal := func(id string, at time.Time, severity int) contracts.SignalEvent {
+		return contracts.SignalEvent{ID: id, Timestamp: at, Source: contracts.SourceWeather, Country: "BR", Region: "global", Commodity: "wheat", Severity: severity, Confidence: 0.8}
+	}
+	process := func(engine *Engine, s contracts.SignalEvent) contracts.RiskEvent {
+		t.Helper()
+		scored, err := engine.Process(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scored
+	}
+
+	a := NewEngine(opts)
+	process(a, signal("s1", start, 8))
+	process(a, signal("s2", start.Add(10*time.Minute), 2))
+
+	snapshots := a.DirtySnapshots()
+	if len(snapshots) != 1 || len(snapshots[0].Signals) != 2 || !snapshots[0].Watermark.Equal(start.Add(10*time.Minute)) {
+		t.Fatalf("snapshots = %+v, want one key with 2 signals", snapshots)
+	}
+	if again := a.DirtySnapshots(); len(again) != 0 {
+		t.Fatalf("dirty flag not cleared: %d snapshots", len(again))
+	}
+	a.MarkDirty(snapshots)
+	if again := a.DirtySnapshots(); len(again) != 1 {
+		t.Fatalf("MarkDirty re-queued %d snapshots, want 1", len(again))
+	}
+
+	// A restored engine scores the next signal as if it had never stopped.
+	b := NewEngine(opts)
+	if got := b.Restore(snapshots); got != 1 {
+		t.Fatalf("restored %d keys, want 1", got)
+	}
+	key := snapshots[0].Key
+	if !b.Has(key) {
+		t.Fatalf("restored engine does not hold %s", key)
+	}
+	next := signal("s3", start.Add(20*time.Minute), 6)
+	if want, got := process(a, next).RiskScore, process(b, next).RiskScore; got != want {
+		t.Fatalf("restored score = %v, want %v", got, want)
+	}
+	if _, err := b.Process(signal("s4", start.Add(-2*time.Hour), 6)); !errors.Is(err, ErrLateSignal) {
+		t.Fatalf("late signal after restore: err = %v, want ErrLateSignal", err)
+	}
+
+	// The key now holds newer state than the snapshot, which is ignored.
+	if got := b.Restore(snapshots); got != 0 {
+		t.Fatalf("stale snapshot restored %d keys, want 0", got)
+	}
+
+	if got := b.Release(map[string]time.Time{key: time.Now().Add(time.Hour)}); got != 1 || b.Has(key) {
+		t.Fatalf("released %d keys, want the key dropped", got)
+	}
+}
+
+// Keys resumed at startup decay on the heartbeat like keys processed here;
+// keys only restored on demand wait for their next signal.
+func TestResumeRescore(t *testing.T) {
+	opts := Options{Limits: Limits{Window: time.Hour}}
+	start := time.Now().UTC()
+	source := NewEngine(opts)
+	if _, err := source.Process(contracts.SignalEvent{ID: "s1", Timestamp: start, Source: contracts.SourceWeather, Country: "BR", Region: "global", Commodity: "wheat", Severity: 8, Confidence: 0.8}); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := source.DirtySnapshots()
+
+	resumed := NewEngine(opts)
+	resumed.Resume(snapshots)
+	events := resumed.Rescore(start.Add(2 * time.Hour))
+	if len(events) != 1 || events[0].RiskScore != 0 || events[0].Trigger != contracts.RiskTriggerHeartbeat {
+		t.Fatalf("resumed rescore = %+v, want one drained heartbeat", events)
+	}
+
+	restored := NewEngine(opts)
+	restored.Restore(snapshots)
+	if events := restored.Rescore(start.Add(2 * time.Hour)); len(events) != 0 {
+		t.Fatalf("restored key rescored: %+v", events)
+	}
+}
@@ -0,0 +1,49 @@
+package risk
+
+import (
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+const DefaultTrendThreshold = 2.0
+
+// minTrendSpan keeps the per-hour rate finite when two scores for a key
+// land within moments of each other.
+const minTrendSpan = time.Minute
+
+type Trend struct {
+	Previous        *float64
+	Delta           float64
+	VelocityPerHour float64
+	Direction       string
+}
+
+// trend compares score against the key's previous score. Deltas within the
+// threshold either way count as stable.
+func trend(state *keyState, score float64, now time.Time, threshold float64) Trend {
+	if !state.scored {
+		return Trend{Direction: contracts.TrendNew}
+	}
+
+	previous := state.lastScore
+	delta := score - previous
+	span := now.Sub(state.lastScoredAt)
+	if span < minTrendSpan {
+		span = minTrendSpan
+	}
+
+	t := Trend{
+		Previous:        &previous,
+		Delta:           round2(delta),
+		VelocityPerHour: round2(delta / span.Hours()),
+		Direction:       contracts.TrendStable,
+	}
+	switch {
+	case delta >= threshold:
+		t.Direction = contracts.TrendRising
+	case delta <= -threshold:
+		t.Direction = contracts.TrendFalling
+	}
+	return t
+}
@@ -0,0 +1,56 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func TestTrend(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		scored       bool
+		score        float64
+		since        time.Duration
+		wantDir      string
+		wantDelta    float64
+		wantVelocity float64
+	}{
+		{"first score", false, 40, time.Hour, contracts.TrendNew, 0, 0},
+		{"rising", true, 60, 2 * time.Hour, contracts.TrendRising, 10, 5},
+		{"rising at threshold", true, 52, time.Hour, contracts.TrendRising, 2, 2},
+		{"stable within threshold", true, 51.5, time.Hour, contracts.TrendStable, 1.5, 1.5},
+		{"stable below", true, 48.5, time.Hour, contracts.TrendStable, -1.5, -1.5},
+		{"falling", true, 35, 30 * time.Minute, contracts.TrendFalling, -15, -30},
+		{"min span", true, 60, time.Second, contracts.TrendRising, 10, 600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &keyState{scored: tt.scored, lastScore: 50, lastScoredAt: at}
+			got := trend(state, tt.score, at.Add(tt.since), DefaultTrendThreshold)
+			if got.Direction != tt.wantDir || got.Delta != tt.wantDelta || got.VelocityPerHour != tt.wantVelocity {
+				t.Fatalf("trend = %+v, want %s delta=%v velocity=%v", got, tt.wantDir, tt.wantDelta, tt.wantVelocity)
+			}
+			if tt.scored && (got.Previous == nil || *got.Previous != 50) {
+				t.Fatalf("previous = %v, want 50", got.Previous)
+			}
+			if !tt.scored && got.Previous != nil {
+				t.Fatalf("previous = %v for the first score", *got.Previous)
+			}
+		})
+	}
+}
+
+func TestActionConditionTrends(t *testing.T) {
+	rules, err := NewActionRules([]ActionRule{actionRule("r", 0, "a", ActionCondition{Trends: []string{contracts.TrendRising}})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for trend, want := range map[string]int{contracts.TrendRising: 1, contracts.TrendStable: 0, contracts.TrendNew: 0} {
+		if got := len(rules.Evaluate(ActionInput{Trend: trend})); got != want {
+			t.Fatalf("trend %s: actions = %d, want %d", trend, got, want)
+		}
+	}
+}
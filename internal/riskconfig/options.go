@@ -0,0 +1,93 @@
+// Package riskconfig builds risk engine options from the service
+// configuration, so that every service constructing an engine scores the
+// same way.
+package riskconfig
+
+import (
+	"fmt"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/config"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+func Options(cfg config.Config) (risk.Options, error) {
+	scorer, err := risk.LookupScorer(cfg.RiskScoringModel)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("scoring model: %w (available: %v)", err, risk.ScorerNames())
+	}
+
+	decayMode, err := risk.ParseDecayMode(cfg.RiskDecayMode)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("decay config: %w", err)
+	}
+
+	clockMode, err := risk.ParseClockMode(cfg.RiskClockMode)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("clock config: %w", err)
+	}
+
+	normalizer, err := risk.LoadNormalizer(cfg.RiskNormalizationFile)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("normalization config: %w", err)
+	}
+
+	policy, err := risk.LoadPolicyStore(cfg.RiskWeightPolicyFile)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("weight policy: %w", err)
+	}
+
+	actions, err := risk.LoadActionRules(cfg.RiskActionRulesFile)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("action rules: %w", err)
+	}
+
+	contagion, err := risk.LoadContagionGraph(cfg.RiskContagionFile)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("contagion graph: %w", err)
+	}
+
+	commodities, err := risk.LoadCommodityGraph(cfg.RiskCommodityGraphFile)
+	if err != nil {
+		return risk.Options{}, fmt.Errorf("commodity graph: %w", err)
+	}
+
+	overrides := make(map[string]risk.Limits)
+	for commodity, window := range cfg.RiskCommodityWindows {
+		o := overrides[commodity]
+		o.Window = window
+		overrides[commodity] = o
+	}
+	for commodity, historyCap := range cfg.RiskCommodityHistoryCaps {
+		o := overrides[commodity]
+		o.HistoryCap = historyCap
+		overrides[commodity] = o
+	}
+
+	return risk.Options{
+		Limits:    risk.Limits{Window: cfg.RiskWindow, HistoryCap: cfg.RiskHistoryCap},
+		Scorer:    scorer,
+		Decay:     risk.Decay{Mode: decayMode, HalfLife: cfg.RiskDecayHalfLife},
+		Overrides: overrides,
+
+		Normalizer:     normalizer,
+		Policy:         policy,
+		Actions:        actions,
+		TrendThreshold: cfg.RiskTrendThreshold,
+		Anomaly: risk.AnomalyConfig{
+			Sigma: cfg.RiskAnomalySigma,
+			Boost: cfg.RiskAnomalyBoost,
+			Alpha: cfg.RiskAnomalyAlpha,
+		},
+		Contagion: risk.ContagionConfig{
+			Graph:    contagion,
+			MaxHops:  cfg.RiskContagionMaxHops,
+			MinDelta: cfg.RiskContagionMinDelta,
+		},
+		Commodities:     commodities,
+		Clock:           clockMode,
+		AllowedLateness: cfg.RiskAllowedLateness,
+		IdleTTL:         cfg.RiskKeyIdleTTL,
+		MaxKeys:         cfg.RiskMaxKeys,
+		Shards:          cfg.RiskShards,
+	}, nil
+}
@@ -0,0 +1,240 @@
+// Package scenario runs hypothetical signals through a private copy of the
+// risk engine and reports how scores and alerts would evolve, without
+// publishing or storing anything.
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/alerting"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+const (
+	MaxSignals = 2000
+	MaxKeys    = 25
+)
+
+var (
+	ErrNoSignals      = errors.New("scenario has no signals")
+	ErrTooManySignals = fmt.Errorf("scenario expands to more than %d signals", MaxSignals)
+	ErrTooManyKeys    = fmt.Errorf("scenario touches more than %d keys", MaxKeys)
+)
+
+// Request is a set of hypothetical signals. When RepeatEveryMinutes and
+// RepeatForHours are set, every signal is repeated at that interval for
+// that long, starting at its own timestamp.
+type Request struct {
+	Signals            []contracts.SignalEvent `json:"signals"`
+	RepeatEveryMinutes int                     `json:"repeat_every_minutes"`
+	RepeatForHours     float64                 `json:"repeat_for_hours"`
+}
+
+// Expand fills in defaults like ingest does, applies repetition and returns the signals in
+// time order.
+func (r Request) Expand(now time.Time) ([]contracts.SignalEvent, error) {
+	if len(r.Signals) == 0 {
+		return nil, ErrNoSignals
+	}
+
+	repeats := 1
+	every := time.Duration(r.RepeatEveryMinutes) * time.Minute
+	if every > 0 && r.RepeatForHours > 0 {
+		repeats = int(time.Duration(r.RepeatForHours*float64(time.Hour))/every) + 1
+	}
+	if len(r.Signals)*repeats > MaxSignals {
+		return nil, ErrTooManySignals
+	}
+
+	keys := make(map[string]bool)
+	out := make([]contracts.SignalEvent, 0, len(r.Signals)*repeats)
+	for i, s := range r.Signals {
+		s.Enrich(now)
+		if s.Country == "" || s.Commodity == "" {
+			return nil, fmt.Errorf("signal %d: country and commodity are required", i)
+		}
+		keys[s.Key()] = true
+		base := s.ID
+		for n := 0; n < repeats; n++ {
+			copied := s
+			copied.Timestamp = s.Timestamp.Add(time.Duration(n) * every)
+			if n > 0 {
+				copied.ID = fmt.Sprintf("%s-%d", base, n)
+			}
+			out = append(out, copied)
+		}
+	}
+	if len(keys) > MaxKeys {
+		return nil, ErrTooManyKeys
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Timestamp.Before(out[j].Timestamp)
+	})
+	return out, nil
+}
+
+// Keys returns every key the signals can affect: their own keys and the
+// keys risk would propagate to. Their current state has to be loaded into
+// the engine before Run.
+func Keys(engine *risk.Engine, signals []contracts.SignalEvent) []string {
+	seen := make(map[string]bool)
+	for _, s := range signals {
+		if seen[s.Key()] {
+			continue
+		}
+		seen[s.Key()] = true
+		for _, target := range engine.Targets(s.Country, s.Region, s.Commodity) {
+			seen[target] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type Point struct {
+	EventID   string    `json:"event_id"`
+	Timestamp time.Time `json:"timestamp"`
+	RiskScore float64   `json:"risk_score"`
+	Trend     string    `json:"trend"`
+	Trigger   string    `json:"trigger"`
+}
+
+type Trajectory struct {
+	Key       string   `json:"key"`
+	Country   string   `json:"country"`
+	Region    string   `json:"region"`
+	Commodity string   `json:"commodity"`
+	Baseline  *float64 `json:"baseline_score"`
+	Final     float64  `json:"final_score"`
+	Peak      float64  `json:"peak_score"`
+	Points    []Point  `json:"points"`
+}
+
+type AlertOutcome struct {
+	Decision  alerting.Decision     `json:"decision"`
+	Timestamp time.Time             `json:"timestamp"`
+	Alert     contracts.AlertRecord `json:"alert"`
+}
+
+type Rejected struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type Result struct {
+	Signals      int            `json:"signals"`
+	Trajectories []Trajectory   `json:"trajectories"`
+	Alerts       []AlertOutcome `json:"alerts"`
+	Rejected     []Rejected     `json:"rejected"`
+}
+
+// State is what the simulation starts from: the last score of each key and
+// the time an alert was opened for keys that already have one.
+type State struct {
+	Baselines  map[string]float64
+	OpenAlerts map[string]time.Time
+}
+
+// Run feeds the signals through the engine in order, propagating each
+// scored event and re-scoring expired windows as the scenario clock moves,
+// and applies the alert policy to every resulting event.
+func Run(engine *risk.Engine, signals []contracts.SignalEvent, policy alerting.Policy, state State) Result {
+	result := Result{
+		Signals:  len(signals),
+		Alerts:   make([]AlertOutcome, 0),
+		Rejected: make([]Rejected, 0),
+	}
+
+	open := make(map[string]time.Time, len(state.OpenAlerts))
+	for key, at := range state.OpenAlerts {
+		open[key] = at
+	}
+	trajectories := make(map[string]*Trajectory)
+
+	record := func(event contracts.RiskEvent) {
+		key := event.Country + "|" + event.Region + "|" + event.Commodity
+		t, ok := trajectories[key]
+		if !ok {
+			t = &Trajectory{Key: key, Country: event.Country, Region: event.Region, Commodity: event.Commodity}
+			if baseline, ok := state.Baselines[key]; ok {
+				t.Baseline = &baseline
+			}
+			trajectories[key] = t
+		}
+		t.Points = append(t.Points, Point{
+			EventID:   event.ID,
+			Timestamp: event.Timestamp,
+			RiskScore: event.RiskScore,
+			Trend:     event.Trend,
+			Trigger:   event.Trigger,
+		})
+		t.Final = event.RiskScore
+		if event.RiskScore > t.Peak {
+			t.Peak = event.RiskScore
+		}
+
+		switch policy.Decide(event) {
+		case alerting.DecisionResolve:
+			if _, ok := open[key]; ok {
+				delete(open, key)
+				alert := policy.NewAlert(event)
+				alert.Status = "resolved"
+				result.Alerts = append(result.Alerts, AlertOutcome{
+					Decision:  alerting.DecisionResolve,
+					Timestamp: event.Timestamp,
+					Alert:     alert,
+				})
+			}
+		case alerting.DecisionOpen:
+			if at, ok := open[key]; ok && event.Timestamp.Sub(at) < policy.Cooldown {
+				return
+			}
+			open[key] = event.Timestamp
+			result.Alerts = append(result.Alerts, AlertOutcome{
+				Decision:  alerting.DecisionOpen,
+				Timestamp: event.Timestamp,
+				Alert:     policy.NewAlert(event),
+			})
+		}
+	}
+	emit := func(event contracts.RiskEvent) {
+		record(event)
+		for _, derived := range engine.Propagate(event) {
+			record(derived)
+		}
+	}
+
+	for _, s := range signals {
+		event, err := engine.Process(s)
+		if err != nil {
+			result.Rejected = append(result.Rejected, Rejected{ID: s.ID, Reason: err.Error()})
+			continue
+		}
+		emit(event)
+		for _, expired := range engine.Rescore(s.Timestamp) {
+			emit(expired)
+		}
+	}
+
+	result.Trajectories = make([]Trajectory, 0, len(trajectories))
+	for _, t := range trajectories {
+		result.Trajectories = append(result.Trajectories, *t)
+	}
+	sort.Slice(result.Trajectories, func(i, j int) bool {
+		if result.Trajectories[i].Peak != result.Trajectories[j].Peak {
+			return result.Trajectories[i].Peak > result.Trajectories[j].Peak
+		}
+		return result.Trajectories[i].Key < result.Trajectories[j].Key
+	})
+	return result
+}
@@ -0,0 +1,177 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/alerting"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+func TestExpand(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     Request
+		wantN   int
+		wantKey string
+		wantErr error
+	}{
+		{
+			name:    "defaults",
+			req:     Request{Signals: []contracts.SignalEvent{{Country: "in", Commodity: "Rice"}}},
+			wantN:   1,
+			wantKey: "IN|global|rice",
+		},
+		{
+			name:    "region case",
+			req:     Request{Signals: []contracts.SignalEvent{{Country: "IN", Region: " Coastal", Commodity: "rice"}}},
+			wantN:   1,
+			wantKey: "IN|coastal|rice",
+		},
+		{
+			name:    "repeated",
+			req:     Request{Signals: []contracts.SignalEvent{{Country: "IN", Region: "west", Commodity: "rice"}}, RepeatEveryMinutes: 30, RepeatForHours: 2},
+			wantN:   5,
+			wantKey: "IN|west|rice",
+		},
+		{name: "empty", req: Request{}, wantErr: ErrNoSignals},
+		{
+			name:    "too many signals",
+			req:     Request{Signals: []contracts.SignalEvent{{Country: "IN", Commodity: "rice"}}, RepeatEveryMinutes: 1, RepeatForHours: 48},
+			wantErr: ErrTooManySignals,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals, err := tt.req.Expand(now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(signals) != tt.wantN {
+				t.Fatalf("signals = %d, want %d", len(signals), tt.wantN)
+			}
+			for i, s := range signals {
+				if s.Key() != tt.wantKey {
+					t.Fatalf("key = %s, want %s", s.Key(), tt.wantKey)
+				}
+				if i > 0 && (s.ID == signals[0].ID || s.Timestamp.Before(signals[i-1].Timestamp)) {
+					t.Fatalf("signal %d: id %s at %s", i, s.ID, s.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandTooManyKeys(t *testing.T) {
+	signals := make([]contracts.SignalEvent, 0, MaxKeys+1)
+	for i := range MaxKeys + 1 {
+		signals = append(signals, contracts.SignalEvent{Country: "IN", Commodity: fmt.Sprintf("c%d", i)})
+	}
+	if _, err := (Request{Signals: signals}).Expand(time.Now()); !errors.Is(err, ErrTooManyKeys) {
+		t.Fatalf("err = %v, want ErrTooManyKeys", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	opts := risk.Options{Clock: risk.ClockEvent, AllowedLateness: time.Hour, Limits: risk.Limits{Window: time.Hour}}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signal := func(id string, at time.Time, severity int) contracts.SignalEvent {
+		s := contracts.SignalEvent{ID: id, Timestamp: at, Source: contracts.SourceNews, Country: "IN", Commodity: "rice", Severity: severity, Confidence: 1}
+		s.Enrich(at)
+		return s
+	}
+	const key = "IN|global|rice"
+
+	// The source engine plays the persisted state the simulator seeds from.
+	source := risk.NewEngine(opts)
+	if _, err := source.Process(signal("seed", start, 6)); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := source.DirtySnapshots()
+	before, err := json.Marshal(snapshots)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signals := []contracts.SignalEvent{
+		signal("s1", start.Add(10*time.Minute), 10),
+		signal("s2", start.Add(20*time.Minute), 10),
+		signal("late", start.Add(-2*time.Hour), 5),
+		// Three hours on the seed and s1, s2 have left the window.
+		signal("s3", start.Add(3*time.Hour), 1),
+	}
+	policy := alerting.Policy{Threshold: 60, Cooldown: 30 * time.Minute, AutoResolveBelow: 40}
+
+	tests := []struct {
+		name   string
+		open   map[string]time.Time
+		alerts []alerting.Decision
+	}{
+		{"opens and resolves", nil, []alerting.Decision{alerting.DecisionOpen, alerting.DecisionResolve}},
+		{"open alert in cooldown", map[string]time.Time{key: start.Add(15 * time.Minute)}, []alerting.Decision{alerting.DecisionResolve}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := risk.NewEngine(opts)
+			engine.Restore(snapshots)
+			result := Run(engine, signals, policy, State{Baselines: map[string]float64{key: *snapshots[0].LastScore}, OpenAlerts: tt.open})
+
+			if len(result.Rejected) != 1 || result.Rejected[0].ID != "late" {
+				t.Fatalf("rejected = %+v, want the late signal", result.Rejected)
+			}
+			var rice *Trajectory
+			for i := range result.Trajectories {
+				if result.Trajectories[i].Key == key {
+					rice = &result.Trajectories[i]
+				}
+			}
+			if rice == nil || rice.Baseline == nil || *rice.Baseline != *snapshots[0].LastScore {
+				t.Fatalf("trajectories = %+v, want %s from its persisted score", result.Trajectories, key)
+			}
+			want := []float64{60, 63.33, 25}
+			if len(rice.Points) != len(want) {
+				t.Fatalf("points = %+v, want scores %v", rice.Points, want)
+			}
+			for i, p := range rice.Points {
+				if p.RiskScore != want[i] {
+					t.Fatalf("point %d score = %v, want %v", i, p.RiskScore, want[i])
+				}
+			}
+			if rice.Peak != 63.33 || rice.Final != 25 {
+				t.Fatalf("peak %v final %v, want 63.33 and 25", rice.Peak, rice.Final)
+			}
+
+			if len(result.Alerts) != len(tt.alerts) {
+				t.Fatalf("alerts = %+v, want %v", result.Alerts, tt.alerts)
+			}
+			for i, a := range result.Alerts {
+				if a.Decision != tt.alerts[i] {
+					t.Fatalf("alert %d = %s, want %s", i, a.Decision, tt.alerts[i])
+				}
+			}
+		})
+	}
+
+	// The simulation ran on its own engine; the source is untouched.
+	after, err := json.Marshal(snapshots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("snapshots changed by the simulation:\n%s\n%s", before, after)
+	}
+	if dirty := source.DirtySnapshots(); len(dirty) != 0 {
+		t.Fatalf("source engine changed: %+v", dirty)
+	}
+	if keys := source.Keys(); len(keys) != 1 || keys[0] != key {
+		t.Fatalf("source keys = %v, want only %s", keys, key)
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+type AnomalyRecord struct {
+	SignalID    string                 `json:"signal_id"`
+	SignalTime  time.Time              `json:"signal_ts"`
+	RiskEventID string                 `json:"risk_event_id"`
+	EventTime   time.Time              `json:"event_ts"`
+	Country     string                 `json:"country"`
+	Region      string                 `json:"region"`
+	Commodity   string                 `json:"commodity"`
+	RiskScore   float64                `json:"risk_score"`
+	Source      contracts.SignalSource `json:"source"`
+	MetricName  string                 `json:"metric_name"`
+	MetricValue float64                `json:"metric_value"`
+	ZScore      float64                `json:"z_score"`
+	Score       float64                `json:"score"`
+}
+
+// insertAnomaly records the flagged signal that triggered event. A signal is
+// recorded once, with the event it triggered.
+func (r *Repository) insertAnomaly(ctx context.Context, event contracts.RiskEvent) error {
+	a := event.Anomaly
+	_, err := r.pool.Exec(ctx, `
+        INSERT INTO signal_anomalies
+            (signal_id, risk_event_id, event_ts, signal_ts, country, region, commodity, risk_score, source, metric_name, metric_value, z_score, score)
+        VALUES
+            ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        ON CONFLICT (signal_id) DO NOTHING
+    `, a.SignalID, event.ID, event.Timestamp, a.Timestamp, event.Country, event.Region, event.Commodity, event.RiskScore, a.Source, a.MetricName, a.MetricValue, a.ZScore, a.Score)
+	if err != nil {
+		return fmt.Errorf("insert anomaly: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListAnomalies(ctx context.Context, country, commodity string, hours, limit int) ([]AnomalyRecord, error) {
+	if hours <= 0 || hours > 168 {
+		hours = 24
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	interval := fmt.Sprintf("%d hours", hours)
+	rows, err := r.pool.Query(ctx, `
+        SELECT signal_id, signal_ts, risk_event_id, event_ts, country, region, commodity, risk_score,
+               source, metric_name, metric_value, z_score, score
+        FROM signal_anomalies
+        WHERE event_ts >= NOW() - $1::interval
+          AND ($2 = '' OR country = $2)
+          AND ($3 = '' OR commodity = $3)
+        ORDER BY event_ts DESC
+        LIMIT $4
+    `, interval, country, commodity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]AnomalyRecord, 0, limit)
+	for rows.Next() {
+		var rec AnomalyRecord
+		if err := rows.Scan(
+			&rec.SignalID,
+			&rec.SignalTime,
+			&rec.RiskEventID,
+			&rec.EventTime,
+			&rec.Country,
+			&rec.Region,
+			&rec.Commodity,
+			&rec.RiskScore,
+			&rec.Source,
+			&rec.MetricName,
+			&rec.MetricValue,
+			&rec.ZScore,
+			&rec.Score,
+		); err != nil {
+			return nil, fmt.Errorf("scan anomaly: %w", err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// catalogQuerier is the pool, or the transaction of a catalog edit.
+type catalogQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *Repository) ListCatalogCommodities(ctx context.Context, category string) ([]contracts.CatalogCommodity, error) {
+	return listCatalogCommodities(ctx, r.pool, category)
+}
+
+func listCatalogCommodities(ctx context.Context, q catalogQuerier, category string) ([]contracts.CatalogCommodity, error) {
+	rows, err := q.Query(ctx, `
+        SELECT name, display_name, category, hs_codes, aliases, updated_at
+        FROM catalog_commodities
+        WHERE ($1 = '' OR category = $1)
+        ORDER BY name
+    `, category)
+	if err != nil {
+		return nil, fmt.Errorf("query catalog commodities: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]contracts.CatalogCommodity, 0)
+	for rows.Next() {
+		item, err := scanCatalogCommodity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan catalog commodity: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *Repository) GetCatalogCommodity(ctx context.Context, name string) (contracts.CatalogCommodity, error) {
+	row := r.pool.QueryRow(ctx, `
+        SELECT name, display_name, category, hs_codes, aliases, updated_at
+        FROM catalog_commodities
+        WHERE name = $1
+    `, name)
+	return scanCatalogCommodity(row)
+}
+
+func scanCatalogCommodity(row pgx.Row) (contracts.CatalogCommodity, error) {
+	var item contracts.CatalogCommodity
+	err := row.Scan(&item.Name, &item.DisplayName, &item.Category, &item.HSCodes, &item.Aliases, &item.UpdatedAt)
+	return item, err
+}
+
+// PutCatalogCommodity upserts a commodity once check accepts the catalog it
+// is handed. See editCatalog.
+func (r *Repository) PutCatalogCommodity(ctx context.Context, item contracts.CatalogCommodity, check CatalogCheck) (contracts.CatalogCommodity, error) {
+	var saved contracts.CatalogCommodity
+	err := r.editCatalog(ctx, check, func(tx pgx.Tx) error {
+		var err error
+		saved, err = upsertCatalogCommodity(ctx, tx, item)
+		return err
+	})
+	return saved, err
+}
+
+func upsertCatalogCommodity(ctx context.Context, q catalogQuerier, item contracts.CatalogCommodity) (contracts.CatalogCommodity, error) {
+	row := q.QueryRow(ctx, `
+        INSERT INTO catalog_commodities (name, display_name, category, hs_codes, aliases, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        ON CONFLICT (name) DO UPDATE
+        SET display_name = EXCLUDED.display_name,
+            category = EXCLUDED.category,
+            hs_codes = EXCLUDED.hs_codes,
+            aliases = EXCLUDED.aliases,
+            updated_at = NOW()
+        RETURNING name, display_name, category, hs_codes, aliases, updated_at
+    `, item.Name, item.DisplayName, item.Category, nonNilStrings(item.HSCodes), nonNilStrings(item.Aliases))
+	saved, err := scanCatalogCommodity(row)
+	if err != nil {
+		return contracts.CatalogCommodity{}, fmt.Errorf("upsert catalog commodity: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *Repository) DeleteCatalogCommodity(ctx context.Context, name string) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM catalog_commodities WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("delete catalog commodity: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *Repository) ListCatalogRegions(ctx context.Context, country string) ([]contracts.CatalogRegion, error) {
+	return listCatalogRegions(ctx, r.pool, country)
+}
+
+func listCatalogRegions(ctx context.Context, q catalogQuerier, country string) ([]contracts.CatalogRegion, error) {
+	rows, err := q.Query(ctx, `
+        SELECT name, display_name, country, COALESCE(parent, ''), aliases, updated_at
+        FROM catalog_regions
+        WHERE ($1 = '' OR country = '' OR country = $1)
+        ORDER BY name, country
+    `, country)
+	if err != nil {
+		return nil, fmt.Errorf("query catalog regions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]contracts.CatalogRegion, 0)
+	for rows.Next() {
+		item, err := scanCatalogRegion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan catalog region: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetCatalogRegion returns the region with exactly this country and name;
+// an empty country is the region valid in every country.
+func (r *Repository) GetCatalogRegion(ctx context.Context, country, name string) (contracts.CatalogRegion, error) {
+	row := r.pool.QueryRow(ctx, `
+        SELECT name, display_name, country, COALESCE(parent, ''), aliases, updated_at
+        FROM catalog_regions
+        WHERE country = $1 AND name = $2
+    `, country, name)
+	return scanCatalogRegion(row)
+}
+
+func scanCatalogRegion(row pgx.Row) (contracts.CatalogRegion, error) {
+	var item contracts.CatalogRegion
+	err := row.Scan(&item.Name, &item.DisplayName, &item.Country, &item.Parent, &item.Aliases, &item.UpdatedAt)
+	return item, err
+}
+
+// PutCatalogRegion upserts a region once check accepts the catalog it is
+// handed. See editCatalog.
+func (r *Repository) PutCatalogRegion(ctx context.Context, item contracts.CatalogRegion, check CatalogCheck) (contracts.CatalogRegion, error) {
+	var saved contracts.CatalogRegion
+	err := r.editCatalog(ctx, check, func(tx pgx.Tx) error {
+		var err error
+		saved, err = upsertCatalogRegion(ctx, tx, item)
+		return err
+	})
+	return saved, err
+}
+
+func upsertCatalogRegion(ctx context.Context, q catalogQuerier, item contracts.CatalogRegion) (contracts.CatalogRegion, error) {
+	row := q.QueryRow(ctx, `
+        INSERT INTO catalog_regions (name, display_name, country, parent, aliases, updated_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5, NOW())
+        ON CONFLICT (country, name) DO UPDATE
+        SET display_name = EXCLUDED.display_name,
+            parent = EXCLUDED.parent,
+            aliases = EXCLUDED.aliases,
+            updated_at = NOW()
+        RETURNING name, display_name, country, COALESCE(parent, ''), aliases, updated_at
+    `, item.Name, item.DisplayName, item.Country, item.Parent, nonNilStrings(item.Aliases))
+	saved, err := scanCatalogRegion(row)
+	if err != nil {
+		return contracts.CatalogRegion{}, fmt.Errorf("upsert catalog region: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteCatalogRegion deletes a region once check accepts the catalog it is
+// handed. See editCatalog.
+func (r *Repository) DeleteCatalogRegion(ctx context.Context, country, name string, check CatalogCheck) error {
+	return r.editCatalog(ctx, check, func(tx pgx.Tx) error {
+		cmd, err := tx.Exec(ctx, `DELETE FROM catalog_regions WHERE country = $1 AND name = $2`, country, name)
+		if err != nil {
+			return fmt.Errorf("delete catalog region: %w", err)
+		}
+		if cmd.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// CatalogCheck validates a catalog edit against the current catalog. Its
+// error is returned unchanged and cancels the edit.
+type CatalogCheck func(commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) error
+
+// editCatalog reads the catalog, runs check on it and applies write in one
+// transaction. The catalog tables are locked against other edits until the
+// commit, so two edits cannot each pass the check and together leave a
+// catalog that fails it. Readers are not blocked.
+func (r *Repository) editCatalog(ctx context.Context, check CatalogCheck, write func(tx pgx.Tx) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin catalog edit: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE catalog_commodities, catalog_regions IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("lock catalog: %w", err)
+	}
+	commodities, err := listCatalogCommodities(ctx, tx, "")
+	if err != nil {
+		return err
+	}
+	regions, err := listCatalogRegions(ctx, tx, "")
+	if err != nil {
+		return err
+	}
+	if err := check(commodities, regions); err != nil {
+		return err
+	}
+	if err := write(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit catalog edit: %w", err)
+	}
+	return nil
+}
+
+// SeedCatalog fills the catalog tables when both are empty, so that a new
+// database starts from the default catalog while deleted entries stay
+// deleted afterwards.
+func (r *Repository) SeedCatalog(ctx context.Context, commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin catalog seed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE catalog_commodities, catalog_regions IN EXCLUSIVE MODE`); err != nil {
+		return false, fmt.Errorf("lock catalog: %w", err)
+	}
+	var populated bool
+	if err := tx.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM catalog_commodities) OR EXISTS (SELECT 1 FROM catalog_regions)
+    `).Scan(&populated); err != nil {
+		return false, fmt.Errorf("check catalog: %w", err)
+	}
+	if populated {
+		return false, nil
+	}
+
+	for _, item := range commodities {
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO catalog_commodities (name, display_name, category, hs_codes, aliases)
+            VALUES ($1, $2, $3, $4, $5)
+        `, item.Name, item.DisplayName, item.Category, nonNilStrings(item.HSCodes), nonNilStrings(item.Aliases)); err != nil {
+			return false, fmt.Errorf("seed commodity %s: %w", item.Name, err)
+		}
+	}
+	for _, item := range regions {
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO catalog_regions (name, display_name, country, parent, aliases)
+            VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+        `, item.Name, item.DisplayName, item.Country, item.Parent, nonNilStrings(item.Aliases)); err != nil {
+			return false, fmt.Errorf("seed region %s: %w", item.Name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit catalog seed: %w", err)
+	}
+	return true, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
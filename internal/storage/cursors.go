@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LoadCursor returns the saved position of a connector, or "" when it has
+// never completed a poll.
+func (r *Repository) LoadCursor(ctx context.Context, name string) (string, error) {
+	var cursor string
+	err := r.pool.QueryRow(ctx, `SELECT cursor FROM connector_cursors WHERE name = $1`, name).Scan(&cursor)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("load cursor %s: %w", name, err)
+	}
+	return cursor, nil
+}
+
+func (r *Repository) SaveCursor(ctx context.Context, name, cursor string) error {
+	_, err := r.pool.Exec(ctx, `
+        INSERT INTO connector_cursors (name, cursor, updated_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (name) DO UPDATE
+        SET cursor = EXCLUDED.cursor,
+            updated_at = NOW()
+    `, name, cursor)
+	if err != nil {
+		return fmt.Errorf("save cursor %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/risk"
+)
+
+func (r *Repository) SaveEngineState(ctx context.Context, snapshots []risk.KeySnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, snap := range snapshots {
+		signals, err := json.Marshal(snap.Signals)
+		if err != nil {
+			return fmt.Errorf("marshal engine state %s: %w", snap.Key, err)
+		}
+		baselines, err := json.Marshal(snap.Baselines)
+		if err != nil {
+			return fmt.Errorf("marshal engine baselines %s: %w", snap.Key, err)
+		}
+		if snap.Baselines == nil {
+			baselines = []byte("{}")
+		}
+		inbound, err := json.Marshal(snap.Inbound)
+		if err != nil {
+			return fmt.Errorf("marshal engine inbound %s: %w", snap.Key, err)
+		}
+		if snap.Inbound == nil {
+			inbound = []byte("{}")
+		}
+		norms, err := json.Marshal(snap.Norms)
+		if err != nil {
+			return fmt.Errorf("marshal engine normalization baselines %s: %w", snap.Key, err)
+		}
+		if snap.Norms == nil {
+			norms = []byte("{}")
+		}
+		batch.Queue(`
+            INSERT INTO risk_engine_state (state_key, signals, watermark, updated_at, last_score, last_scored_at, baselines, inbound, normalization_baselines)
+            VALUES ($1, $2::jsonb, $3, $4, $5, $6, $7::jsonb, $8::jsonb, $9::jsonb)
+            ON CONFLICT (state_key) DO UPDATE
+            SET signals = EXCLUDED.signals,
+                watermark = EXCLUDED.watermark,
+                updated_at = EXCLUDED.updated_at,
+                last_score = EXCLUDED.last_score,
+                last_scored_at = EXCLUDED.last_scored_at,
+                baselines = EXCLUDED.baselines,
+                inbound = EXCLUDED.inbound,
+                normalization_baselines = EXCLUDED.normalization_baselines
+            WHERE risk_engine_state.updated_at <= EXCLUDED.updated_at
+        `, snap.Key, string(signals), nullableTime(snap.Watermark), snap.UpdatedAt, snap.LastScore, nullableTime(snap.LastScoredAt), string(baselines), string(inbound), string(norms))
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("save engine state: %w", err)
+	}
+	return nil
+}
+
+// EngineStateChangedSince returns the updated_at of the snapshots updated
+// after since. It reads the updated_at index, so its cost follows the write
+// rate rather than the number of keys.
+func (r *Repository) EngineStateChangedSince(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	rows, err := r.pool.Query(ctx, `
+        SELECT state_key, updated_at
+        FROM risk_engine_state
+        WHERE updated_at > $1
+    `, since)
+	if err != nil {
+		return nil, fmt.Errorf("query engine state versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]time.Time)
+	for rows.Next() {
+		var key string
+		var updatedAt time.Time
+		if err := rows.Scan(&key, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan engine state version: %w", err)
+		}
+		versions[key] = updatedAt
+	}
+	return versions, rows.Err()
+}
+
+// LoadEngineState returns every snapshot updated at or after since.
+func (r *Repository) LoadEngineState(ctx context.Context, since time.Time) ([]risk.KeySnapshot, error) {
+	rows, err := r.pool.Query(ctx, `
+        SELECT state_key, signals, watermark, updated_at, last_score, last_scored_at, baselines, inbound, normalization_baselines
+        FROM risk_engine_state
+        WHERE updated_at >= $1
+    `, since)
+	if err != nil {
+		return nil, fmt.Errorf("query engine state: %w", err)
+	}
+	return scanEngineState(rows)
+}
+
+// LoadEngineStateKeys returns the persisted state of the given keys only.
+func (r *Repository) LoadEngineStateKeys(ctx context.Context, keys []string) ([]risk.KeySnapshot, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT state_key, signals, watermark, updated_at, last_score, last_scored_at, baselines, inbound, normalization_baselines
+        FROM risk_engine_state
+        WHERE state_key = ANY($1)
+    `, keys)
+	if err != nil {
+		return nil, fmt.Errorf("query engine state keys: %w", err)
+	}
+	return scanEngineState(rows)
+}
+
+func scanEngineState(rows pgx.Rows) ([]risk.KeySnapshot, error) {
+	defer rows.Close()
+
+	snapshots := make([]risk.KeySnapshot, 0)
+	for rows.Next() {
+		var snap risk.KeySnapshot
+		var signalsRaw, baselinesRaw, inboundRaw, normsRaw []byte
+		var watermark, lastScoredAt *time.Time
+		if err := rows.Scan(&snap.Key, &signalsRaw, &watermark, &snap.UpdatedAt, &snap.LastScore, &lastScoredAt, &baselinesRaw, &inboundRaw, &normsRaw); err != nil {
+			return nil, fmt.Errorf("scan engine state: %w", err)
+		}
+		if err := json.Unmarshal(signalsRaw, &snap.Signals); err != nil {
+			return nil, fmt.Errorf("decode engine state %s: %w", snap.Key, err)
+		}
+		if err := json.Unmarshal(baselinesRaw, &snap.Baselines); err != nil {
+			return nil, fmt.Errorf("decode engine baselines %s: %w", snap.Key, err)
+		}
+		if err := json.Unmarshal(inboundRaw, &snap.Inbound); err != nil {
+			return nil, fmt.Errorf("decode engine inbound %s: %w", snap.Key, err)
+		}
+		if err := json.Unmarshal(normsRaw, &snap.Norms); err != nil {
+			return nil, fmt.Errorf("decode engine normalization baselines %s: %w", snap.Key, err)
+		}
+		if watermark != nil {
+			snap.Watermark = *watermark
+		}
+		if lastScoredAt != nil {
+			snap.LastScoredAt = *lastScoredAt
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	return snapshots, rows.Err()
+}
+
+func (r *Repository) PruneEngineState(ctx context.Context, before time.Time) (int64, error) {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM risk_engine_state WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune engine state: %w", err)
+	}
+	return cmd.RowsAffected(), nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ScoreBucket is the mean risk score of one key over one hour.
+type ScoreBucket struct {
+	Country      string    `json:"country"`
+	Region       string    `json:"region"`
+	Commodity    string    `json:"commodity"`
+	BucketStart  time.Time `json:"bucket_start"`
+	AvgRiskScore float64   `json:"avg_risk_score"`
+	RiskEvents   int       `json:"risk_events"`
+}
+
+// ScoreHistory returns hourly score buckets per key over the last hours,
+// ordered by key and time. Hours without events are absent.
+func (r *Repository) ScoreHistory(ctx context.Context, country, region, commodity string, hours int) ([]ScoreBucket, error) {
+	if hours <= 0 || hours > 720 {
+		hours = 168
+	}
+	interval := fmt.Sprintf("%d hours", hours)
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT
+            country,
+            region,
+            commodity,
+            date_trunc('hour', event_ts) AS bucket_start,
+            AVG(risk_score) AS avg_risk_score,
+            COUNT(*) AS risk_events
+        FROM risk_events
+        WHERE event_ts >= NOW() - $1::interval
+          AND ($2 = '' OR country = $2)
+          AND ($3 = '' OR region = $3)
+          AND ($4 = '' OR commodity = $4)
+        GROUP BY country, region, commodity, bucket_start
+        ORDER BY country, region, commodity, bucket_start ASC
+    `, interval, country, region, commodity)
+	if err != nil {
+		return nil, fmt.Errorf("score history query: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := make([]ScoreBucket, 0, 256)
+	for rows.Next() {
+		var b ScoreBucket
+		if err := rows.Scan(&b.Country, &b.Region, &b.Commodity, &b.BucketStart, &b.AvgRiskScore, &b.RiskEvents); err != nil {
+			return nil, fmt.Errorf("score history scan: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, nil
+}
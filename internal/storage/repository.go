@@ -26,28 +26,57 @@ func (r *Repository) InsertRiskEvent(ctx context.Context, event contracts.RiskEv
 	if err != nil {
 		return fmt.Errorf("marshal contributors: %w", err)
 	}
+	actions, err := json.Marshal(nonNilActions(event.Actions))
+	if err != nil {
+		return fmt.Errorf("marshal actions: %w", err)
+	}
+	var breakdown any
+	if event.Breakdown != nil {
+		raw, err := json.Marshal(event.Breakdown)
+		if err != nil {
+			return fmt.Errorf("marshal breakdown: %w", err)
+		}
+		breakdown = string(raw)
+	}
+	var stderr, lower, upper, level *float64
+	if iv := event.Uncertainty; iv != nil {
+		stderr, lower, upper, level = &iv.StdErr, &iv.Lower, &iv.Upper, &iv.Level
+	}
 
 	_, err = r.pool.Exec(ctx, `
         INSERT INTO risk_events
-            (id, event_ts, country, region, commodity, risk_score, window_minutes, contributors, recommended_action)
+            (id, event_ts, country, region, commodity, risk_score, window_minutes, contributors, recommended_action, trigger, policy_version, actions,
+             previous_score, score_delta, velocity_per_hour, trend, anomaly_count, signal_count, breakdown,
+             score_stderr, score_lower, score_upper, score_level)
         VALUES
-            ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9)
+            ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9, $10, $11, $12::jsonb, $13, $14, $15, $16, $17, $18, $19::jsonb, $20, $21, $22, $23)
         ON CONFLICT (id) DO NOTHING
-    `, event.ID, event.Timestamp, event.Country, event.Region, event.Commodity, event.RiskScore, event.WindowMinutes, string(contributors), event.RecommendedAction)
+    `, event.ID, event.Timestamp, event.Country, event.Region, event.Commodity, event.RiskScore, event.WindowMinutes, string(contributors), event.RecommendedAction, triggerOrDefault(event.Trigger), event.PolicyVersion, string(actions),
+		event.PreviousScore, event.ScoreDelta, event.VelocityPerHour, trendOrDefault(event.Trend), event.AnomalyCount, event.SignalCount, breakdown,
+		stderr, lower, upper, level)
 	if err != nil {
 		return fmt.Errorf("insert risk event: %w", err)
 	}
 
+	if event.Anomaly != nil {
+		if err := r.insertAnomaly(ctx, event); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+const riskEventColumns = `id, event_ts, country, region, commodity, risk_score, window_minutes, contributors, recommended_action, trigger, policy_version, actions,
+               previous_score, score_delta, velocity_per_hour, trend, anomaly_count, signal_count,
+               score_stderr, score_lower, score_upper, score_level`
+
 func (r *Repository) ListRiskEvents(ctx context.Context, country, commodity string, limit int) ([]contracts.RiskEvent, error) {
 	if limit <= 0 || limit > 500 {
 		limit = 100
 	}
 
 	rows, err := r.pool.Query(ctx, `
-        SELECT id, event_ts, country, region, commodity, risk_score, window_minutes, contributors, recommended_action
+        SELECT `+riskEventColumns+`
         FROM risk_events
         WHERE ($1 = '' OR country = $1)
           AND ($2 = '' OR commodity = $2)
@@ -61,29 +90,80 @@ func (r *Repository) ListRiskEvents(ctx context.Context, country, commodity stri
 
 	results := make([]contracts.RiskEvent, 0, limit)
 	for rows.Next() {
-		var event contracts.RiskEvent
-		var contributorsRaw []byte
-		if err := rows.Scan(
-			&event.ID,
-			&event.Timestamp,
-			&event.Country,
-			&event.Region,
-			&event.Commodity,
-			&event.RiskScore,
-			&event.WindowMinutes,
-			&contributorsRaw,
-			&event.RecommendedAction,
-		); err != nil {
-			return nil, fmt.Errorf("scan risk event: %w", err)
+		event, err := scanRiskEvent(rows)
+		if err != nil {
+			return nil, err
 		}
-
-		_ = json.Unmarshal(contributorsRaw, &event.Contributors)
 		results = append(results, event)
 	}
 
 	return results, nil
 }
 
+// GetRiskEvent returns one risk event with its stored score breakdown.
+// Events written before breakdowns were recorded have a nil Breakdown.
+func (r *Repository) GetRiskEvent(ctx context.Context, id string) (contracts.RiskEvent, error) {
+	var breakdownRaw []byte
+	row := r.pool.QueryRow(ctx, `
+        SELECT `+riskEventColumns+`, breakdown
+        FROM risk_events
+        WHERE id = $1
+    `, id)
+	event, err := scanRiskEvent(row, &breakdownRaw)
+	if err != nil {
+		return contracts.RiskEvent{}, err
+	}
+
+	if len(breakdownRaw) > 0 {
+		event.Breakdown = &contracts.RiskBreakdown{}
+		if err := json.Unmarshal(breakdownRaw, event.Breakdown); err != nil {
+			return contracts.RiskEvent{}, fmt.Errorf("decode risk breakdown: %w", err)
+		}
+	}
+	return event, nil
+}
+
+// scanRiskEvent reads riskEventColumns followed by any extra columns.
+func scanRiskEvent(row pgx.Row, extra ...any) (contracts.RiskEvent, error) {
+	var event contracts.RiskEvent
+	var contributorsRaw, actionsRaw []byte
+	var stderr, lower, upper, level *float64
+	dest := []any{
+		&event.ID,
+		&event.Timestamp,
+		&event.Country,
+		&event.Region,
+		&event.Commodity,
+		&event.RiskScore,
+		&event.WindowMinutes,
+		&contributorsRaw,
+		&event.RecommendedAction,
+		&event.Trigger,
+		&event.PolicyVersion,
+		&actionsRaw,
+		&event.PreviousScore,
+		&event.ScoreDelta,
+		&event.VelocityPerHour,
+		&event.Trend,
+		&event.AnomalyCount,
+		&event.SignalCount,
+		&stderr,
+		&lower,
+		&upper,
+		&level,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return contracts.RiskEvent{}, fmt.Errorf("scan risk event: %w", err)
+	}
+
+	_ = json.Unmarshal(contributorsRaw, &event.Contributors)
+	_ = json.Unmarshal(actionsRaw, &event.Actions)
+	if stderr != nil && lower != nil && upper != nil && level != nil {
+		event.Uncertainty = &contracts.ScoreInterval{StdErr: *stderr, Lower: *lower, Upper: *upper, Level: *level}
+	}
+	return event, nil
+}
+
 func (r *Repository) HasOpenAlertInCooldown(ctx context.Context, country, region, commodity string, cooldown time.Duration) (bool, error) {
 	var exists bool
 	err := r.pool.QueryRow(ctx, `
@@ -121,6 +201,23 @@ func (r *Repository) InsertAlert(ctx context.Context, alert contracts.AlertRecor
 	return nil
 }
 
+func (r *Repository) ResolveOpenAlerts(ctx context.Context, country, region, commodity string) (int64, error) {
+	cmd, err := r.pool.Exec(ctx, `
+        UPDATE alerts
+        SET status = 'resolved',
+            updated_at = NOW(),
+            resolved_at = NOW()
+        WHERE status IN ('open', 'acknowledged')
+          AND country = $1
+          AND region = $2
+          AND commodity = $3
+    `, country, region, commodity)
+	if err != nil {
+		return 0, fmt.Errorf("resolve open alerts: %w", err)
+	}
+	return cmd.RowsAffected(), nil
+}
+
 func (r *Repository) ListAlerts(ctx context.Context, status string, limit int) ([]contracts.AlertRecord, error) {
 	if limit <= 0 || limit > 500 {
 		limit = 100
@@ -291,7 +388,7 @@ func (r *Repository) Hotspots(ctx context.Context, hours, limit int) ([]Hotspot,
             re.region,
             re.commodity,
             ROUND(AVG(re.risk_score)::numeric, 2)::float8 AS avg_risk_score,
-            MAX(re.risk_score) AS latest_risk_score,
+            (ARRAY_AGG(re.risk_score ORDER BY re.event_ts DESC))[1] AS latest_risk_score,
             MAX(re.event_ts) AS last_event_at,
             COALESCE((
                 SELECT COUNT(*)
@@ -338,3 +435,31 @@ func nullableUUID(v string) any {
 	}
 	return v
 }
+
+func nonNilActions(v []contracts.RecommendedAction) []contracts.RecommendedAction {
+	if v == nil {
+		return []contracts.RecommendedAction{}
+	}
+	return v
+}
+
+func trendOrDefault(v string) string {
+	if v == "" {
+		return contracts.TrendNew
+	}
+	return v
+}
+
+func triggerOrDefault(v string) string {
+	if v == "" {
+		return contracts.RiskTriggerSignal
+	}
+	return v
+}
+
+func nullableTime(v time.Time) any {
+	if v.IsZero() {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS risk_engine_state (
+  state_key TEXT PRIMARY KEY,
+  signals JSONB NOT NULL,
+  watermark TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_engine_state_updated
+  ON risk_engine_state(updated_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE risk_events
+  ADD COLUMN IF NOT EXISTS trigger TEXT NOT NULL DEFAULT 'signal';
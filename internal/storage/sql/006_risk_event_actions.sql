@@ -0,0 +1,2 @@
+ALTER TABLE risk_events
+  ADD COLUMN IF NOT EXISTS actions JSONB NOT NULL DEFAULT '[]'::jsonb;
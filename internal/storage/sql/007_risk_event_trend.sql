@@ -0,0 +1,9 @@
+ALTER TABLE risk_events
+  ADD COLUMN IF NOT EXISTS previous_score DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS score_delta DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS velocity_per_hour DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS trend TEXT NOT NULL DEFAULT 'new';
+
+ALTER TABLE risk_engine_state
+  ADD COLUMN IF NOT EXISTS last_score DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS last_scored_at TIMESTAMPTZ;
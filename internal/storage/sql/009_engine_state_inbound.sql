@@ -0,0 +1,2 @@
+ALTER TABLE risk_engine_state
+  ADD COLUMN IF NOT EXISTS inbound JSONB NOT NULL DEFAULT '{}'::jsonb;
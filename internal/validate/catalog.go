@@ -0,0 +1,214 @@
+package validate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+//go:embed defaults/catalog.json
+var defaultCatalog []byte
+
+// RootRegion is the top of the region hierarchy and the region of signals
+// that do not name one.
+const RootRegion = "global"
+
+// Catalog is the set of commodities and regions ingest accepts. Names and
+// aliases resolve to the canonical name. Regions are keyed by country and
+// name, so two countries can each define their own region of a name.
+type Catalog struct {
+	Version     string                       `json:"version"`
+	Commodities []contracts.CatalogCommodity `json:"commodities"`
+	Regions     []contracts.CatalogRegion    `json:"regions"`
+
+	commodities map[string]string
+	regions     map[string]contracts.CatalogRegion // by regionID
+}
+
+// LoadCatalog reads a catalog from path, or the embedded default when path
+// is empty.
+func LoadCatalog(path string) (*Catalog, error) {
+	body := defaultCatalog
+	if strings.TrimSpace(path) != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read catalog: %w", err)
+		}
+		body = raw
+	}
+
+	var c Catalog
+	if err := json.Unmarshal(body, &c); err != nil {
+		return nil, fmt.Errorf("parse catalog: %w", err)
+	}
+	return NewCatalog(c.Version, c.Commodities, c.Regions)
+}
+
+// NewCatalog canonicalizes names and checks that names and aliases are
+// unambiguous within a country and that every region's parent exists
+// without forming a cycle.
+func NewCatalog(version string, commodities []contracts.CatalogCommodity, regions []contracts.CatalogRegion) (*Catalog, error) {
+	c := &Catalog{
+		Version:     version,
+		Commodities: make([]contracts.CatalogCommodity, 0, len(commodities)),
+		Regions:     make([]contracts.CatalogRegion, 0, len(regions)),
+		commodities: make(map[string]string),
+		regions:     make(map[string]contracts.CatalogRegion),
+	}
+
+	for i, entry := range commodities {
+		entry.Name = CanonicalName(entry.Name)
+		if entry.Name == "" {
+			return nil, fmt.Errorf("commodity %d: name is required", i)
+		}
+		if _, ok := c.commodities[entry.Name]; ok {
+			return nil, fmt.Errorf("commodity %q is listed twice", entry.Name)
+		}
+		c.commodities[entry.Name] = entry.Name
+		c.Commodities = append(c.Commodities, entry)
+	}
+	for _, entry := range c.Commodities {
+		for _, alias := range entry.Aliases {
+			if err := addAlias(c.commodities, CanonicalName(alias), entry.Name); err != nil {
+				return nil, fmt.Errorf("commodity %w", err)
+			}
+		}
+	}
+
+	names := make(map[string]string)
+	for i, entry := range regions {
+		entry.Name = CanonicalName(entry.Name)
+		entry.Parent = CanonicalName(entry.Parent)
+		entry.Country = strings.ToUpper(strings.TrimSpace(entry.Country))
+		if entry.Name == "" {
+			return nil, fmt.Errorf("region %d: name is required", i)
+		}
+		id := regionID(entry.Country, entry.Name)
+		if _, ok := c.regions[id]; ok {
+			return nil, fmt.Errorf("region %q is listed twice", id)
+		}
+		if entry.Country != "" && !IsCountry(entry.Country) {
+			return nil, fmt.Errorf("region %q: %q is not an ISO 3166-1 alpha-2 code", entry.Name, entry.Country)
+		}
+		names[id] = id
+		c.regions[id] = entry
+		c.Regions = append(c.Regions, entry)
+	}
+	for _, entry := range c.Regions {
+		for _, alias := range entry.Aliases {
+			alias = CanonicalName(alias)
+			if alias == "" {
+				continue
+			}
+			if err := addAlias(names, regionID(entry.Country, alias), regionID(entry.Country, entry.Name)); err != nil {
+				return nil, fmt.Errorf("region %w", err)
+			}
+		}
+		if err := c.checkParent(entry); err != nil {
+			return nil, err
+		}
+	}
+	for alias, id := range names {
+		if alias != id {
+			c.regions[alias] = c.regions[id]
+		}
+	}
+	return c, nil
+}
+
+// regionID is the key of a region: its name, prefixed with the country
+// for regions bound to one.
+func regionID(country, name string) string {
+	if country == "" {
+		return name
+	}
+	return country + "/" + name
+}
+
+func addAlias(names map[string]string, alias, name string) error {
+	if alias == "" || alias == name {
+		return nil
+	}
+	if existing, ok := names[alias]; ok && existing != name {
+		return fmt.Errorf("alias %q of %q is already %q", alias, name, existing)
+	}
+	names[alias] = name
+	return nil
+}
+
+func (c *Catalog) checkParent(entry contracts.CatalogRegion) error {
+	seen := map[string]bool{regionID(entry.Country, entry.Name): true}
+	for current := entry; current.Parent != ""; {
+		parent, ok := c.lookupRegion(current.Country, current.Parent)
+		if !ok {
+			return fmt.Errorf("region %q: parent %q is not in the catalog", current.Name, current.Parent)
+		}
+		if parent.Country != "" && parent.Country != entry.Country {
+			return fmt.Errorf("region %q: parent %q belongs to %s", current.Name, parent.Name, parent.Country)
+		}
+		id := regionID(parent.Country, parent.Name)
+		if seen[id] {
+			return fmt.Errorf("region %q: parent chain loops through %q", entry.Name, parent.Name)
+		}
+		seen[id] = true
+		current = parent
+	}
+	return nil
+}
+
+// ResolveCommodity returns the canonical name of a commodity or alias.
+func (c *Catalog) ResolveCommodity(commodity string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	name, ok := c.commodities[CanonicalName(commodity)]
+	return name, ok
+}
+
+// ResolveRegion returns the catalog region for a name or alias in a
+// country. A region bound to the country takes precedence over an unbound
+// one of the same name; regions bound to other countries are not found.
+func (c *Catalog) ResolveRegion(country, region string) (contracts.CatalogRegion, bool) {
+	if c == nil {
+		return contracts.CatalogRegion{}, false
+	}
+	return c.lookupRegion(strings.ToUpper(strings.TrimSpace(country)), CanonicalName(region))
+}
+
+func (c *Catalog) lookupRegion(country, name string) (contracts.CatalogRegion, bool) {
+	if country != "" {
+		if entry, ok := c.regions[regionID(country, name)]; ok {
+			return entry, true
+		}
+	}
+	entry, ok := c.regions[name]
+	return entry, ok
+}
+
+// Ancestors returns the parents of a region in a country from the nearest
+// to the root.
+func (c *Catalog) Ancestors(country, region string) []string {
+	entry, ok := c.ResolveRegion(country, region)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for entry.Parent != "" {
+		out = append(out, entry.Parent)
+		entry, _ = c.lookupRegion(entry.Country, entry.Parent)
+	}
+	return out
+}
+
+// CanonicalName lower-cases a name and joins its words with underscores,
+// so "Crude Oil", "crude-oil" and " crude_oil" are the same entry.
+func CanonicalName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
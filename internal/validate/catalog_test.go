@@ -0,0 +1,110 @@
+package validate
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+func testRegions() []contracts.CatalogRegion {
+	return []contracts.CatalogRegion{
+		{Name: "global"},
+		{Name: "west", Parent: "global", Aliases: []string{"western"}},
+		{Name: "west", Country: "IN", Parent: "global", Aliases: []string{"paschim"}},
+		{Name: "gujarat", Country: "IN", Parent: "west", Aliases: []string{"gj"}},
+		{Name: "west", Country: "US", Parent: "global"},
+	}
+}
+
+func TestNewCatalogRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []contracts.CatalogRegion
+		wantErr bool
+	}{
+		{"same name in several countries", testRegions(), false},
+		{"listed twice in a country", append(testRegions(), contracts.CatalogRegion{Name: "West", Country: "in", Parent: "global"}), true},
+		{"alias taken in the same country", append(testRegions(), contracts.CatalogRegion{Name: "kutch", Country: "IN", Parent: "gujarat", Aliases: []string{"gj"}}), true},
+		{"alias reused in another country", append(testRegions(), contracts.CatalogRegion{Name: "georgia", Country: "US", Parent: "global", Aliases: []string{"gj"}}), false},
+		{"parent missing", append(testRegions(), contracts.CatalogRegion{Name: "kutch", Country: "IN", Parent: "saurashtra"}), true},
+		{"parent in another country", append(testRegions(), contracts.CatalogRegion{Name: "texas", Country: "US", Parent: "gujarat"}), true},
+		{"unassigned country", append(testRegions(), contracts.CatalogRegion{Name: "west", Country: "XX", Parent: "global"}), true},
+		{"parent loop", []contracts.CatalogRegion{
+			{Name: "a", Country: "IN", Parent: "b"},
+			{Name: "b", Country: "IN", Parent: "a"},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCatalog("test", nil, tt.regions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveRegion(t *testing.T) {
+	catalog, err := NewCatalog("test", nil, testRegions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		country, region string
+		want            string // country/name of the entry, "" when not found
+	}{
+		{"IN", "west", "IN/west"},
+		{"in", "Paschim", "IN/west"},
+		{"US", "west", "US/west"},
+		{"BR", "Western", "west"},
+		{"IN", "western", "west"},
+		{"IN", "gj", "IN/gujarat"},
+		{"BR", "gujarat", ""},
+		{"", "west", "west"},
+	}
+	for _, tt := range tests {
+		entry, ok := catalog.ResolveRegion(tt.country, tt.region)
+		got := ""
+		if ok {
+			got = regionID(entry.Country, entry.Name)
+		}
+		if got != tt.want {
+			t.Fatalf("ResolveRegion(%q, %q) = %q, want %q", tt.country, tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	catalog, err := NewCatalog("test", nil, testRegions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := catalog.Ancestors("IN", "gujarat"), []string{"west", "global"}; !slices.Equal(got, want) {
+		t.Fatalf("ancestors = %v, want %v", got, want)
+	}
+	if got := catalog.Ancestors("BR", "gujarat"); got != nil {
+		t.Fatalf("ancestors of a foreign region = %v, want none", got)
+	}
+}
+
+// Signals resolve a region in their own country, so the same name lands on
+// each country's entry.
+func TestCheckRegionCountry(t *testing.T) {
+	catalog, err := NewCatalog("test", []contracts.CatalogCommodity{{Name: "wheat"}}, testRegions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New(Options{Catalog: catalog})
+
+	s := validSignal()
+	s.Country, s.Region = "IN", "GJ"
+	if _, err := v.Check(&s, ModeStrict); err != nil || s.Region != "gujarat" {
+		t.Fatalf("IN/gj: region %q, err %v", s.Region, err)
+	}
+	s = validSignal()
+	s.Country, s.Region = "BR", "gujarat"
+	if _, err := v.Check(&s, ModeLenient); err == nil {
+		t.Fatal("region bound to IN accepted for BR")
+	}
+}
@@ -0,0 +1,224 @@
+// Package validate checks incoming signals against the signal schema: ISO
+// 3166 countries, known sources, the commodity and region catalog, finite
+// metric values and timestamps that are not too far in the future.
+package validate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+// Mode decides what happens to a problem that can be fixed. Strict rejects
+// the signal; lenient normalizes the field and reports a warning.
+type Mode string
+
+const (
+	ModeStrict  Mode = "strict"
+	ModeLenient Mode = "lenient"
+)
+
+// ParseMode accepts "strict" and "lenient", case-insensitively.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case ModeStrict:
+		return ModeStrict, nil
+	case ModeLenient:
+		return ModeLenient, nil
+	default:
+		return "", fmt.Errorf("unknown validation mode %q", s)
+	}
+}
+
+const (
+	CodeRequired   = "required"
+	CodeInvalid    = "invalid"
+	CodeUnknown    = "unknown"
+	CodeOutOfRange = "out_of_range"
+	CodeNotFinite  = "not_finite"
+	CodeFuture     = "in_future"
+)
+
+// Problem is one field that failed validation.
+type Problem struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Field + ": " + p.Message
+}
+
+// Problems is returned as the error of a rejected signal.
+type Problems []Problem
+
+func (p Problems) Error() string {
+	parts := make([]string, 0, len(p))
+	for _, problem := range p {
+		parts = append(parts, problem.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// defaultSources are the sources of the signal schema, used when no
+// source list is configured.
+var defaultSources = []contracts.SignalSource{
+	contracts.SourceShippingLane,
+	contracts.SourcePortCongestion,
+	contracts.SourceWeather,
+	contracts.SourcePriceSpike,
+	contracts.SourceNews,
+}
+
+type Options struct {
+	Catalog *Catalog
+	// Sources are the known signal sources, normally those of the risk
+	// weight policy. Empty means the schema's built-in sources.
+	Sources []contracts.SignalSource
+	// MaxFuture is how far ahead of now a timestamp may be, to allow for
+	// clock skew between producers.
+	MaxFuture time.Duration
+}
+
+type Validator struct {
+	catalog   atomic.Pointer[Catalog]
+	sources   atomic.Pointer[map[contracts.SignalSource]bool]
+	maxFuture time.Duration
+	now       func() time.Time
+}
+
+func New(opts Options) *Validator {
+	v := &Validator{
+		maxFuture: opts.MaxFuture,
+		now:       time.Now,
+	}
+	v.catalog.Store(opts.Catalog)
+	v.SetSources(opts.Sources)
+	return v
+}
+
+// SetSources replaces the known sources used by later checks. Empty
+// restores the built-in sources.
+func (v *Validator) SetSources(sources []contracts.SignalSource) {
+	if len(sources) == 0 {
+		sources = defaultSources
+	}
+	known := make(map[contracts.SignalSource]bool, len(sources))
+	for _, source := range sources {
+		known[source] = true
+	}
+	v.sources.Store(&known)
+}
+
+// SetCatalog replaces the catalog used by later checks.
+func (v *Validator) SetCatalog(c *Catalog) {
+	v.catalog.Store(c)
+}
+
+func (v *Validator) Catalog() *Catalog {
+	return v.catalog.Load()
+}
+
+// Check validates s in place, expecting defaults to have been filled in
+// already. Commodity and region are rewritten to their catalog names.
+// Problems that cannot be fixed, such as an unassigned country or a
+// commodity or region missing from the catalog, always reject the signal.
+// In lenient mode the others are fixed and returned as warnings, and an
+// unknown source is kept as sent with a warning; in strict mode they reject
+// it too.
+func (v *Validator) Check(s *contracts.SignalEvent, mode Mode) (warnings Problems, err error) {
+	var fatal Problems
+	fixable := func(p Problem, fix func()) {
+		if mode == ModeStrict {
+			fatal = append(fatal, p)
+			return
+		}
+		fix()
+		warnings = append(warnings, p)
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(s.Country))
+	switch {
+	case country == "":
+		fatal = append(fatal, Problem{Field: "country", Code: CodeRequired, Message: "country is required"})
+	case IsCountry(country):
+		s.Country = country
+	case countryAliases[country] != "":
+		alias := countryAliases[country]
+		fixable(Problem{Field: "country", Code: CodeInvalid, Message: fmt.Sprintf("%q is not an ISO 3166-1 code, use %q", country, alias)}, func() {
+			s.Country = alias
+		})
+	default:
+		fatal = append(fatal, Problem{Field: "country", Code: CodeInvalid, Message: fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 code", s.Country)})
+	}
+
+	catalog := v.catalog.Load()
+	commodity := strings.ToLower(strings.TrimSpace(s.Commodity))
+	switch {
+	case commodity == "":
+		fatal = append(fatal, Problem{Field: "commodity", Code: CodeRequired, Message: "commodity is required"})
+	case catalog == nil:
+		s.Commodity = commodity
+	default:
+		if name, ok := catalog.ResolveCommodity(commodity); ok {
+			s.Commodity = name
+		} else {
+			fatal = append(fatal, Problem{Field: "commodity", Code: CodeUnknown, Message: fmt.Sprintf("%q is not in the commodity catalog", commodity)})
+		}
+	}
+
+	region := strings.TrimSpace(s.Region)
+	if region == "" {
+		region = RootRegion
+	}
+	if catalog == nil {
+		s.Region = region
+	} else if entry, ok := catalog.ResolveRegion(s.Country, region); !ok {
+		fatal = append(fatal, Problem{Field: "region", Code: CodeUnknown, Message: fmt.Sprintf("%q is not in the region catalog for %s", region, s.Country)})
+	} else {
+		s.Region = entry.Name
+	}
+
+	if known := *v.sources.Load(); !known[s.Source] {
+		// The source is kept: the risk engine scores sources missing from
+		// its weight policy with the default weight and flags them.
+		fixable(Problem{Field: "source", Code: CodeUnknown, Message: fmt.Sprintf("%q is not a known source", s.Source)}, func() {})
+	}
+
+	if math.IsNaN(s.MetricValue) || math.IsInf(s.MetricValue, 0) {
+		fatal = append(fatal, Problem{Field: "metric_value", Code: CodeNotFinite, Message: "metric_value must be a finite number"})
+	}
+
+	if s.Severity < 1 || s.Severity > 10 {
+		severity := s.Severity
+		fixable(Problem{Field: "severity", Code: CodeOutOfRange, Message: fmt.Sprintf("severity %d is outside 1-10", severity)}, func() {
+			s.Severity = min(max(severity, 1), 10)
+		})
+	}
+
+	if math.IsNaN(s.Confidence) {
+		fatal = append(fatal, Problem{Field: "confidence", Code: CodeNotFinite, Message: "confidence must be a finite number"})
+	} else if s.Confidence <= 0 || s.Confidence > 1 {
+		confidence := s.Confidence
+		fixable(Problem{Field: "confidence", Code: CodeOutOfRange, Message: fmt.Sprintf("confidence %g is outside (0, 1]", confidence)}, func() {
+			s.Confidence = math.Min(math.Max(confidence, 0.01), 1)
+		})
+	}
+
+	now := v.now().UTC()
+	if v.maxFuture > 0 && s.Timestamp.After(now.Add(v.maxFuture)) {
+		fixable(Problem{Field: "timestamp", Code: CodeFuture, Message: fmt.Sprintf("timestamp %s is more than %s ahead", s.Timestamp.UTC().Format(time.RFC3339), v.maxFuture)}, func() {
+			s.Timestamp = now
+		})
+	}
+
+	if len(fatal) > 0 {
+		return warnings, fatal
+	}
+	return warnings, nil
+}
@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shiroonigami23-ui/global-supply-shock-platform/internal/contracts"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testValidator(t *testing.T, sources ...contracts.SignalSource) *Validator {
+	t.Helper()
+	catalog, err := LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New(Options{Catalog: catalog, Sources: sources, MaxFuture: 5 * time.Minute})
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+func validSignal() contracts.SignalEvent {
+	return contracts.SignalEvent{
+		ID:         "s1",
+		Timestamp:  testNow,
+		Source:     contracts.SourceWeather,
+		Country:    "BR",
+		Region:     "west",
+		Commodity:  "wheat",
+		Severity:   5,
+		Confidence: 0.8,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		edit         func(*contracts.SignalEvent)
+		wantWarnings int
+		wantStrict   bool // accepted in strict mode
+		wantLenient  bool // accepted in lenient mode
+		check        func(*testing.T, contracts.SignalEvent)
+	}{
+		{"valid", func(*contracts.SignalEvent) {}, 0, true, true, nil},
+		{"aliases", func(s *contracts.SignalEvent) { s.Commodity = "Durum Wheat"; s.Region = "Western" }, 0, true, true,
+			func(t *testing.T, s contracts.SignalEvent) {
+				if s.Commodity != "wheat" || s.Region != "west" {
+					t.Fatalf("canonical names = %s/%s", s.Commodity, s.Region)
+				}
+			}},
+		{"country alias", func(s *contracts.SignalEvent) { s.Country = "uk" }, 1, false, true,
+			func(t *testing.T, s contracts.SignalEvent) {
+				if s.Country != "GB" {
+					t.Fatalf("country = %s, want GB", s.Country)
+				}
+			}},
+		{"unassigned country", func(s *contracts.SignalEvent) { s.Country = "XX" }, 0, false, false, nil},
+		{"unknown commodity", func(s *contracts.SignalEvent) { s.Commodity = "unobtainium" }, 0, false, false, nil},
+		{"unknown region", func(s *contracts.SignalEvent) { s.Region = "atlantis" }, 0, false, false, nil},
+		{"not finite", func(s *contracts.SignalEvent) { s.MetricValue = math.Inf(1) }, 0, false, false, nil},
+		{"severity clamped", func(s *contracts.SignalEvent) { s.Severity = 14 }, 1, false, true,
+			func(t *testing.T, s contracts.SignalEvent) {
+				if s.Severity != 10 {
+					t.Fatalf("severity = %d, want 10", s.Severity)
+				}
+			}},
+		{"future timestamp", func(s *contracts.SignalEvent) { s.Timestamp = testNow.Add(time.Hour) }, 1, false, true,
+			func(t *testing.T, s contracts.SignalEvent) {
+				if !s.Timestamp.Equal(testNow) {
+					t.Fatalf("timestamp = %s, want now", s.Timestamp)
+				}
+			}},
+		{"unknown source kept", func(s *contracts.SignalEvent) { s.Source = "satellite" }, 1, false, true,
+			func(t *testing.T, s contracts.SignalEvent) {
+				if s.Source != "satellite" {
+					t.Fatalf("source = %s, want it kept as sent", s.Source)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := testValidator(t)
+			for _, mode := range []Mode{ModeStrict, ModeLenient} {
+				s := validSignal()
+				tt.edit(&s)
+				warnings, err := v.Check(&s, mode)
+				want := tt.wantStrict
+				if mode == ModeLenient {
+					want = tt.wantLenient
+				}
+				if (err == nil) != want {
+					t.Fatalf("%s: err = %v, want accepted %v", mode, err, want)
+				}
+				if mode == ModeLenient && err == nil {
+					if len(warnings) != tt.wantWarnings {
+						t.Fatalf("warnings = %v, want %d", warnings, tt.wantWarnings)
+					}
+					if tt.check != nil {
+						tt.check(t, s)
+					}
+				}
+			}
+		})
+	}
+}
+
+// The known sources follow the weight policy rather than a fixed list.
+func TestSetSources(t *testing.T) {
+	v := testValidator(t, contracts.SourceWeather, "satellite")
+
+	s := validSignal()
+	s.Source = "satellite"
+	if _, err := v.Check(&s, ModeStrict); err != nil {
+		t.Fatalf("policy source rejected: %v", err)
+	}
+	s.Source = contracts.SourceNews
+	if _, err := v.Check(&s, ModeStrict); err == nil {
+		t.Fatal("source missing from the policy accepted in strict mode")
+	}
+
+	v.SetSources(nil)
+	if _, err := v.Check(&s, ModeStrict); err != nil {
+		t.Fatalf("built-in source rejected after reset: %v", err)
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE risk_engine_state
+  ADD COLUMN IF NOT EXISTS normalization_baselines JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,28 @@
+ALTER TABLE risk_events
+  ADD COLUMN IF NOT EXISTS anomaly_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_risk_events_anomalies
+  ON risk_events(event_ts DESC)
+  WHERE anomaly_count > 0;
+
+ALTER TABLE risk_engine_state
+  ADD COLUMN IF NOT EXISTS baselines JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE TABLE IF NOT EXISTS signal_anomalies (
+  signal_id TEXT PRIMARY KEY,
+  risk_event_id TEXT NOT NULL,
+  event_ts TIMESTAMPTZ NOT NULL,
+  signal_ts TIMESTAMPTZ NOT NULL,
+  country TEXT NOT NULL,
+  region TEXT NOT NULL,
+  commodity TEXT NOT NULL,
+  risk_score DOUBLE PRECISION NOT NULL,
+  source TEXT NOT NULL,
+  metric_name TEXT NOT NULL,
+  metric_value DOUBLE PRECISION NOT NULL,
+  z_score DOUBLE PRECISION NOT NULL,
+  score DOUBLE PRECISION NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_signal_anomalies_event_ts
+  ON signal_anomalies(event_ts DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE risk_events
+  ADD COLUMN IF NOT EXISTS signal_count INT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS breakdown JSONB;
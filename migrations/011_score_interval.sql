@@ -0,0 +1,5 @@
+ALTER TABLE risk_events
+  ADD COLUMN IF NOT EXISTS score_stderr DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS score_lower DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS score_upper DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS score_level DOUBLE PRECISION;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS catalog_commodities (
+  name TEXT PRIMARY KEY,
+  display_name TEXT NOT NULL DEFAULT '',
+  category TEXT NOT NULL DEFAULT '',
+  hs_codes TEXT[] NOT NULL DEFAULT '{}',
+  aliases TEXT[] NOT NULL DEFAULT '{}',
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_commodities_category
+  ON catalog_commodities(category);
+
+-- Regions are unique per country; country is empty for regions valid in
+-- every country. A parent is looked up in the region's country first, so it
+-- cannot be a foreign key and is checked by the query-api instead.
+CREATE TABLE IF NOT EXISTS catalog_regions (
+  country TEXT NOT NULL DEFAULT '',
+  name TEXT NOT NULL,
+  display_name TEXT NOT NULL DEFAULT '',
+  parent TEXT,
+  aliases TEXT[] NOT NULL DEFAULT '{}',
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (country, name)
+);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS connector_cursors (
+  name TEXT PRIMARY KEY,
+  cursor TEXT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);